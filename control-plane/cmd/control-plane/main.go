@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,17 +18,43 @@ import (
 
 func main() {
 
+	configFile := flag.String(
+		"config",
+		os.Getenv("CONFIG_FILE"),
+		"YAML configuration file (env: CONFIG_FILE); environment variables override it",
+	)
+	printConfig := flag.Bool(
+		"print-config",
+		false,
+		"print the effective configuration, secrets redacted, and exit",
+	)
+	flag.Parse()
+
 	//-----------------------------------------
 	// Load configuration
 	//-----------------------------------------
 
-	cfg := config.Load()
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *printConfig {
+		out, err := cfg.YAML()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(out)
+		return
+	}
 
 	//-----------------------------------------
 	// Initialize logger
 	//-----------------------------------------
 
-	logger, err := logging.New(cfg.Log.Level)
+	logger, logLevel, err := logging.New(cfg.Log.Level)
 	if err != nil {
 		panic(err)
 	}
@@ -37,17 +65,15 @@ func main() {
 	logger.Info("starting control plane",
 		zap.String("service", cfg.ServiceName),
 		zap.String("environment", cfg.Environment),
+		zap.String("config_file", *configFile),
+		zap.Any("config", cfg.Redacted()),
 	)
 
 	//-----------------------------------------
 	// Construct server (composition root)
 	//-----------------------------------------
 
-	srv, err := server.New(
-		cfg.HTTP.Address,
-		cfg.HTTP.ReadTimeout,
-		cfg.HTTP.WriteTimeout,
-	)
+	srv, err := server.New(cfg, logger, logLevel)
 	if err != nil {
 		logger.Fatal("failed to construct server", zap.Error(err))
 	}
@@ -60,6 +86,7 @@ func main() {
 
 		logger.Info("http server listening",
 			zap.String("address", cfg.HTTP.Address),
+			zap.Bool("tls", cfg.HTTP.TLS.Enabled()),
 		)
 
 		if err := srv.Start(); err != nil && err != http.ErrServerClosed {
@@ -68,18 +95,38 @@ func main() {
 	}()
 
 	//-----------------------------------------
-	// Graceful shutdown
+	// Reload on SIGHUP, shut down gracefully on SIGINT/SIGTERM
 	//-----------------------------------------
 
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		for range hup {
+			logger.Info("reload signal received", zap.String("config_file", *configFile))
+
+			next, err := config.Load(*configFile)
+			if err != nil {
+				logger.Error("configuration reload rejected; keeping the running configuration", zap.Error(err))
+				continue
+			}
+
+			if err := srv.Reload(next); err != nil {
+				logger.Error("configuration reload failed; keeping the running configuration", zap.Error(err))
+			}
+		}
+	}()
+
 	<-stop
 
 	logger.Info("shutdown signal received")
 
 	ctx, cancel := context.WithTimeout(
 		context.Background(),
-		cfg.HTTP.ShutdownTimeout,
+		cfg.HTTP.ShutdownTimeout.Duration,
 	)
 	defer cancel()
 
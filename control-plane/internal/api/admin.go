@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+)
+
+// LogLevel reads (GET) or changes (PUT {"level":"debug"}) the process
+// log level without a restart. Platform admins only.
+func (h *Handlers) LogLevel(w http.ResponseWriter, r *http.Request) {
+
+	if !h.authorize(w, r, authz.ActionManageLogging, authz.Resource{Kind: kindLogging, Name: "log-level"}) {
+		return
+	}
+
+	before := h.logLevel.Level()
+
+	h.logLevel.ServeHTTP(w, r)
+
+	if after := h.logLevel.Level(); after != before {
+		h.log(r).Warn("log level changed",
+			zap.Stringer("from", before),
+			zap.Stringer("to", after),
+		)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/audit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+)
+
+// ListAudit queries the audit log, newest first.
+//
+// Query parameters (all optional):
+//
+//	?service=payments
+//	?environment=pr-42
+//	?principal=token:ci-bot
+//	?action=environment.create
+//	?outcome=denied
+//	?since=2025-01-01T00:00:00Z&until=2025-02-01T00:00:00Z
+//	?limit=100
+//
+// Owners may read their service's records; the full log is for
+// platform admins.
+func (h *Handlers) ListAudit(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	q := audit.Query{
+		Principal:   params.Get("principal"),
+		Action:      params.Get("action"),
+		Service:     params.Get("service"),
+		Environment: params.Get("environment"),
+		Outcome:     audit.Outcome(params.Get("outcome")),
+	}
+
+	if !h.authorize(w, r, authz.ActionReadAudit, h.serviceResource(kindAudit, q.Service, q.Service)) {
+		return
+	}
+
+	var err error
+
+	if q.Since, err = parseTimeParam(params.Get("since")); err != nil {
+		http.Error(w, "invalid since: expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+	if q.Until, err = parseTimeParam(params.Get("until")); err != nil {
+		http.Error(w, "invalid until: expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+
+	if raw := params.Get("limit"); raw != "" {
+		if q.Limit, err = strconv.Atoi(raw); err != nil || q.Limit < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	records, err := h.audit.Query(r.Context(), q)
+	if err != nil {
+		h.log(r).Error("failed to query audit log", zap.Error(err))
+		http.Error(w, "failed to query audit log", http.StatusInternalServerError)
+		return
+	}
+
+	if records == nil {
+		records = []audit.Record{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(AuditListResponse{Records: records})
+}
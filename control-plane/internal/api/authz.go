@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/audit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/logging"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/problem"
+)
+
+// Resource kinds used in authorization decisions.
+const (
+	kindService     = "service"
+	kindEnvironment = "environment"
+	kindWorkflow    = "workflow"
+	kindTeam        = "team"
+	kindAudit       = "audit"
+	kindLogging     = "logging"
+)
+
+// authorize checks the request's principal against the ownership
+// policy and writes a 403 problem when denied.
+func (h *Handlers) authorize(
+	w http.ResponseWriter,
+	r *http.Request,
+	action authz.Action,
+	res authz.Resource,
+) bool {
+
+	environment := ""
+	if res.Kind == kindEnvironment {
+		environment = res.Name
+	}
+	audit.Annotate(r.Context(), string(action), res.Service, environment)
+
+	if res.Service != "" {
+		logging.AddFields(r.Context(), zap.String("service", res.Service))
+	}
+	if environment != "" {
+		logging.AddFields(r.Context(), zap.String("environment", environment))
+	}
+
+	d := h.authz.Authorize(r.Context(), auth.FromRequest(r), action, res)
+	if !d.Allowed {
+		problem.Write(w, http.StatusForbidden, d.Reason)
+		return false
+	}
+
+	return true
+}
+
+// serviceResource describes a resource belonging to a service.
+// Unregistered services have no owner; members of the service's
+// team share ownership.
+func (h *Handlers) serviceResource(kind, name, service string) authz.Resource {
+
+	res := authz.Resource{
+		Kind:    kind,
+		Name:    name,
+		Service: service,
+	}
+
+	if svc, err := h.store.Get(service); err == nil {
+		res.Owner = svc.Owner
+		res.Team = svc.Team
+
+		if team, err := h.store.GetTeam(svc.Team); err == nil {
+			res.Members = team.Members
+		}
+	}
+
+	return res
+}
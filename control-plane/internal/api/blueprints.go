@@ -0,0 +1,22 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ListBlueprints returns the kinds of environment that can be created.
+func (h *Handlers) ListBlueprints(w http.ResponseWriter, r *http.Request) {
+	defaultName := h.blueprints.Default()
+
+	out := make([]BlueprintResponse, 0)
+	for _, b := range h.blueprints.List() {
+		out = append(out, ToBlueprintResponse(b, b.Name == defaultName))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"blueprints": out,
+	})
+}
@@ -1,10 +1,81 @@
 package api
 
+import "github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/pipeline"
+
 // CreateServiceRequest is the external API contract used by clients
 // registering a service with the control plane.
+//
+// Owner is a user, or a group as "group:<name>" (see
+// authz.GroupOwnerPrefix); it defaults to the registering principal.
+//
+// Blueprint is the default blueprint of the service's environments.
 type CreateServiceRequest struct {
 	Name        string `json:"name"`
 	Owner       string `json:"owner"`
+	Team        string `json:"team"`
+	Cluster     string `json:"cluster"`
 	RepoURL     string `json:"repo_url"`
+	Language    string `json:"language"`
 	Environment string `json:"environment"`
+	Blueprint   string `json:"blueprint,omitempty"`
+}
+
+// CreateTeamRequest registers or updates a team.
+//
+// Members are user names or subjects. Unset quotas fall back to the
+// platform defaults, if any, and are otherwise unlimited.
+type CreateTeamRequest struct {
+	Name      string             `json:"name"`
+	Members   []string           `json:"members"`
+	Namespace string             `json:"namespace"`
+	Cluster   string             `json:"cluster"`
+	Quotas    TeamQuotasContract `json:"quotas"`
+}
+
+// TeamQuotasContract is the wire form of a team's quotas.
+type TeamQuotasContract struct {
+	MaxEnvironments             int     `json:"max_environments,omitempty"`
+	MaxTTL                      string  `json:"max_ttl,omitempty"`
+	MaxConcurrentRuns           int     `json:"max_concurrent_runs,omitempty"`
+	MaxEnvironmentHoursPerMonth float64 `json:"max_environment_hours_per_month,omitempty"`
+}
+
+// CreateRunRequest is the external API contract used to trigger
+// a CI run for a registered service.
+//
+// Event and Branch are checked against the trigger rules of the
+// service's .platform.yaml. Event is "api" (the default) or
+// "pull_request"; "push" is reserved for repository webhooks.
+//
+// Neither is taken on trust. A pull_request event names PullRequest,
+// and runs its head against its base branch. A Branch without a
+// Revision runs the branch; with one, the branch must contain it.
+type CreateRunRequest struct {
+	Revision    string            `json:"revision"`
+	Branch      string            `json:"branch"`
+	Event       string            `json:"event"`
+	PullRequest int               `json:"pull_request"`
+	Parameters  map[string]string `json:"parameters"`
+}
+
+// CreateDeploymentRequest deploys a build of a registered service into
+// an environment.
+//
+// Image is an image reference, or another artifact the deploy template
+// understands. Template selects a deploy template by name; without one
+// the catalog's deploy template is used. Parameters are passed to it,
+// subject to its allowlist (see catalog.AnnotationUserParameters).
+type CreateDeploymentRequest struct {
+	Service    string            `json:"service"`
+	Image      string            `json:"image"`
+	Revision   string            `json:"revision"`
+	Template   string            `json:"template"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// CreatePipelineRequest submits (or renders) a declarative pipeline
+// for a registered service.
+type CreatePipelineRequest struct {
+	Service  string        `json:"service"`
+	Pipeline pipeline.Spec `json:"pipeline"`
 }
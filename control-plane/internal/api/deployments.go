@@ -0,0 +1,255 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/contract"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+)
+
+// CreateDeployment deploys a build of a registered service into a
+// ready environment: one whose create workflow has succeeded and that
+// is not being destroyed.
+//
+// The caller must be allowed to deploy both the service and into the
+// environment, which may belong to another service.
+func (h *Handlers) CreateDeployment(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	env, err := h.store.GetEnvironment(name)
+	if err != nil {
+		http.Error(w, "environment not found", http.StatusNotFound)
+		return
+	}
+
+	var req CreateDeploymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	if req.Service == "" || req.Image == "" {
+		http.Error(w, "service and image are required", http.StatusBadRequest)
+		return
+	}
+
+	spec := orchestrator.DeploymentSpec{
+		Service:    req.Service,
+		Image:      req.Image,
+		Revision:   req.Revision,
+		Template:   req.Template,
+		Trigger:    orchestrator.TriggerAPI,
+		Parameters: req.Parameters,
+	}
+
+	if err := spec.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.store.Get(req.Service); err != nil {
+		http.Error(w, "unknown service "+req.Service, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionDeploy, h.serviceResource(kindService, req.Service, req.Service)) {
+		return
+	}
+	if !h.authorize(w, r, authz.ActionDeploy, h.serviceResource(kindEnvironment, name, env.Spec.Service)) {
+		return
+	}
+
+	//-----------------------------------------
+	// Environment readiness
+	//-----------------------------------------
+
+	if env.DestroyWorkflow != nil {
+		http.Error(w, "environment is being destroyed", http.StatusConflict)
+		return
+	}
+
+	createStatus, err := h.envOrchestrator.GetCreateStatus(r.Context(), env)
+	if err != nil {
+		h.log(r).Error("failed to read environment status", zap.Error(err))
+		http.Error(w, "failed to read environment status", http.StatusBadGateway)
+		return
+	}
+
+	if createStatus.Phase != wf.WorkflowSucceeded {
+		http.Error(w, "environment is not ready (create workflow "+string(createStatus.Phase)+")", http.StatusConflict)
+		return
+	}
+
+	// The namespace to deploy into may be an output.
+	h.captureOutputs(r, env, createStatus)
+
+	release, ok := h.acquireSubmission(w, r, req.Service)
+	if !ok {
+		return
+	}
+	defer release()
+
+	d, err := h.deployOrchestrator.Deploy(r.Context(), env, spec)
+	if err != nil {
+		h.log(r).Error("failed to deploy",
+			zap.String("deployed_service", req.Service),
+			zap.Error(err),
+		)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, catalog.ErrTemplateNotFound) ||
+			errors.Is(err, catalog.ErrInvalidParameters) ||
+			errors.Is(err, orchestrator.ErrInvalidDeployment) ||
+			errors.Is(err, contract.ErrViolation) {
+			status = http.StatusUnprocessableEntity
+		}
+
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if _, err := h.store.UpdateEnvironment(name, func(env *orchestrator.Environment) {
+		env.Deployments = append(env.Deployments, d)
+	}); err != nil {
+		h.log(r).Error("failed to record deployment", zap.Error(err))
+		http.Error(w, "failed to record deployment", http.StatusInternalServerError)
+		return
+	}
+
+	h.log(r).Info("deployment accepted",
+		zap.String("deployed_service", req.Service),
+		zap.String("image", req.Image),
+		zap.String("workflow", d.Workflow.Name),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(ToDeploymentResponse(d, ""))
+}
+
+// ListDeployments returns an environment's deployment history, newest
+// first, and the version currently deployed per service.
+func (h *Handlers) ListDeployments(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	env, err := h.store.GetEnvironment(name)
+	if err != nil {
+		http.Error(w, "environment not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionReadEnvironment, h.serviceResource(kindEnvironment, name, env.Spec.Service)) {
+		return
+	}
+
+	phases := h.refreshDeployments(r.Context(), env)
+
+	deployments := make([]DeploymentResponse, 0, len(env.Deployments))
+	for i := len(env.Deployments) - 1; i >= 0; i-- {
+		deployments = append(deployments, ToDeploymentResponse(env.Deployments[i], phases[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"deployments": deployments,
+		"deployed":    deployedResponse(env),
+	})
+}
+
+//
+// ---- Helpers ----
+//
+
+// refreshDeployments returns the phase of each of the environment's
+// deployments and records those that have completed, on env and in
+// the store. Workflows that cannot be read report an empty phase.
+func (h *Handlers) refreshDeployments(
+	ctx context.Context,
+	env *orchestrator.Environment,
+) []wf.WorkflowPhase {
+
+	phases := make([]wf.WorkflowPhase, len(env.Deployments))
+	completed := make(map[string]wf.WorkflowPhase)
+
+	for i, d := range env.Deployments {
+		if d.Completed() {
+			phases[i] = d.Phase
+			continue
+		}
+
+		status, err := h.deployOrchestrator.GetDeploymentStatus(ctx, d)
+		if err != nil {
+			continue
+		}
+
+		phases[i] = status.Phase
+		if status.Phase.Completed() {
+			d.Phase = status.Phase
+			completed[d.Workflow.Name] = status.Phase
+		}
+	}
+
+	if len(completed) > 0 {
+		_, _ = h.store.UpdateEnvironment(env.Spec.Name, func(stored *orchestrator.Environment) {
+			for _, d := range stored.Deployments {
+				if phase, ok := completed[d.Workflow.Name]; ok {
+					d.Phase = phase
+				}
+			}
+		})
+	}
+
+	return phases
+}
+
+// deployedResponse is the version currently deployed per service.
+func deployedResponse(env *orchestrator.Environment) map[string]DeploymentResponse {
+
+	deployed := env.Deployed()
+
+	out := make(map[string]DeploymentResponse, len(deployed))
+	for service, d := range deployed {
+		out[service] = ToDeploymentResponse(d, d.Phase)
+	}
+
+	return out
+}
+
+// captureOutputs records the outputs of the environment's create
+// workflow once it has succeeded, on env and in the store. They are
+// read once and kept: the workflow may be garbage collected later.
+func (h *Handlers) captureOutputs(
+	r *http.Request,
+	env *orchestrator.Environment,
+	createStatus *wf.WorkflowStatus,
+) {
+
+	if env.Outputs != nil || createStatus == nil || createStatus.Phase != wf.WorkflowSucceeded {
+		return
+	}
+
+	outputs, err := orchestrator.CaptureOutputs(env, createStatus)
+	if err != nil {
+		h.log(r).Warn("some environment outputs were not captured",
+			zap.String("environment", env.Spec.Name),
+			zap.Error(err),
+		)
+	}
+
+	env.Outputs = outputs
+
+	// Only the first capture is kept, should requests race.
+	_, _ = h.store.UpdateEnvironment(env.Spec.Name, func(stored *orchestrator.Environment) {
+		if stored.Outputs == nil {
+			stored.Outputs = outputs
+		}
+	})
+}
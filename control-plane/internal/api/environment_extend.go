@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/problem"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/quota"
+)
+
+// ExtendEnvironmentRequest is the external API contract used to
+// extend a live environment.
+//
+// TTL is the environment's new total lifetime, counted from its
+// creation like the TTL it was created with, so that repeated
+// extensions stay within the platform and team maximums.
+type ExtendEnvironmentRequest struct {
+	TTL string `json:"ttl"`
+}
+
+// ExtendEnvironment lengthens a live environment's TTL by replacing
+// its TTL cleanup workflow.
+func (h *Handlers) ExtendEnvironment(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	var req ExtendEnvironmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	ttl, err := time.ParseDuration(req.TTL)
+	if err != nil {
+		http.Error(w, "invalid ttl", http.StatusBadRequest)
+		return
+	}
+
+	env, err := h.store.GetEnvironment(name)
+	if err != nil {
+		http.Error(w, "environment not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionExtendEnvironment, h.serviceResource(kindEnvironment, name, env.Spec.Service)) {
+		return
+	}
+
+	now := time.Now()
+
+	if env.DestroyWorkflow != nil || !now.Before(env.CreateWorkflow.SubmittedAt.Add(env.Spec.TTL)) {
+		http.Error(w, "environment "+name+" has been destroyed or has expired", http.StatusConflict)
+		return
+	}
+
+	if ttl <= env.Spec.TTL {
+		http.Error(w, fmt.Sprintf(
+			"ttl %s does not extend the current ttl of %s",
+			ttl,
+			env.Spec.TTL,
+		), http.StatusUnprocessableEntity)
+		return
+	}
+
+	if max := h.policy.Get().MaxTTL; max > 0 && ttl > max {
+		problem.Write(w, http.StatusForbidden, fmt.Sprintf(
+			"ttl %s exceeds the platform maximum of %s",
+			ttl,
+			max,
+		))
+		return
+	}
+
+	if service, err := h.store.Get(env.Spec.Service); err == nil {
+		team, ok := h.owningTeam(w, r, service)
+		if !ok {
+			return
+		}
+
+		err := quota.CheckExtension(
+			team.Name,
+			h.teamLimits(team),
+			h.store.EnvironmentsOfTeam(team.Name),
+			env,
+			ttl,
+			now,
+		)
+		if !h.writeQuotaError(w, r, err) {
+			return
+		}
+	}
+
+	release, ok := h.acquireSubmission(w, r, env.Spec.Service)
+	if !ok {
+		return
+	}
+	defer release()
+
+	ttlRef, err := h.envOrchestrator.Extend(r.Context(), env, ttl)
+	if err != nil {
+		h.log(r).Error("failed to extend environment", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := h.store.UpdateEnvironment(name, func(env *orchestrator.Environment) {
+		env.Spec.TTL = ttl
+		env.TTLWorkflow = ttlRef
+	}); err != nil {
+		h.log(r).Error("failed to record ttl workflow", zap.Error(err))
+		http.Error(w, "failed to record ttl workflow", http.StatusInternalServerError)
+		return
+	}
+
+	h.log(r).Info("environment extended",
+		zap.String("environment", name),
+		zap.Duration("ttl", ttl),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"name":         name,
+		"ttl_seconds":  int64(ttl.Seconds()),
+		"expires_at":   env.CreateWorkflow.SubmittedAt.Add(ttl).UTC(),
+		"ttl_workflow": ToWorkflowReferenceResponse(*ttlRef),
+	})
+}
@@ -5,15 +5,16 @@ import (
 	"net/http"
 
 	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
-	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
 )
 
 // GetEnvironment returns a unified, live view of an environment.
 //
 // It:
-// - Does NOT read from storage
-// - Does NOT cache
-// - Queries execution state on demand
+// - Reads ONLY workflow references from storage
+// - Does NOT cache execution state
+// - Queries execution state on demand, on the hosting cluster
 func (h *Handlers) GetEnvironment(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -24,27 +25,38 @@ func (h *Handlers) GetEnvironment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// ---- resolve environment from request context ----
-	// Assumption: environment was attached by earlier middleware or handler
-	env, ok := ctx.Value("environment").(*orchestrator.Environment)
-	if !ok || env == nil {
+	// ---- resolve environment references ----
+	// Only workflow references are stored; state below is live.
+	env, err := h.store.GetEnvironment(envName)
+	if err != nil {
 		http.Error(w, "environment not found", http.StatusNotFound)
 		return
 	}
 
+	if !h.authorize(w, r, authz.ActionReadEnvironment, h.serviceResource(kindEnvironment, envName, env.Spec.Service)) {
+		return
+	}
+
 	// ---- query live workflow statuses ----
 
 	createStatus, _ := h.envOrchestrator.GetCreateStatus(ctx, env)
 	ttlStatus, _ := h.envOrchestrator.GetTTLStatus(ctx, env)
 
+	h.captureOutputs(r, env, createStatus)
+	h.refreshDeployments(ctx, env)
+
 	// ---- assemble response (inline, no extra types) ----
 
 	resp := map[string]interface{}{
 		"environment": map[string]interface{}{
 			"name":        env.Spec.Name,
 			"service":     env.Spec.Service,
+			"cluster":     env.Spec.Cluster,
+			"blueprint":   env.Spec.Blueprint,
 			"ttl_seconds": int64(env.Spec.TTL.Seconds()),
 			"parameters":  env.Spec.Parameters,
+			"outputs":     env.Outputs,
+			"deployed":    deployedResponse(env),
 		},
 		"workflows": map[string]interface{}{
 			"create": map[string]interface{}{
@@ -54,6 +66,14 @@ func (h *Handlers) GetEnvironment(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	if env.Terraform != nil {
+		resp["environment"].(map[string]interface{})["terraform"] = map[string]interface{}{
+			"module":    env.Terraform.Module,
+			"version":   env.Terraform.Version,
+			"state_key": env.Terraform.StateKey,
+		}
+	}
+
 	if env.DestroyWorkflow != nil {
 		resp["workflows"].(map[string]interface{})["destroy"] = map[string]interface{}{
 			"reference": ToWorkflowReferenceResponse(*env.DestroyWorkflow),
@@ -1,39 +1,96 @@
 package api
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
-
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/audit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/health"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/logging"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/manifest"
 	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/problem"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/ratelimit"
 )
 
 // Handlers owns all HTTP handlers for the control-plane API.
 // Dependencies are injected explicitly.
 type Handlers struct {
-	store           *ServiceStore
-	envOrchestrator orchestrator.EnvironmentOrchestrator
-	logger          *zap.Logger
+	store              *ServiceStore
+	envOrchestrator    orchestrator.EnvironmentOrchestrator
+	ciOrchestrator     orchestrator.CIOrchestrator
+	deployOrchestrator orchestrator.DeploymentOrchestrator
+	search             orchestrator.WorkflowSearch
+	templates          *catalog.Catalog
+	blueprints         *blueprint.Registry
+	manifests          *manifest.Loader
+	authz              *authz.Authorizer
+	submissions        *ratelimit.Concurrency
+	policy             *PolicySource
+	audit              audit.Store
+	readiness          *health.Checker
+	logLevel           zap.AtomicLevel
+	logger             *zap.Logger
 }
 
 func NewHandlers(
 	store *ServiceStore,
 	envOrchestrator orchestrator.EnvironmentOrchestrator,
+	ciOrchestrator orchestrator.CIOrchestrator,
+	deployOrchestrator orchestrator.DeploymentOrchestrator,
+	search orchestrator.WorkflowSearch,
+	templates *catalog.Catalog,
+	blueprints *blueprint.Registry,
+	manifests *manifest.Loader,
+	authorizer *authz.Authorizer,
+	submissions *ratelimit.Concurrency,
+	policy *PolicySource,
+	auditLog audit.Store,
+	readiness *health.Checker,
+	logLevel zap.AtomicLevel,
 	logger *zap.Logger,
 ) *Handlers {
 	return &Handlers{
-		store:           store,
-		envOrchestrator: envOrchestrator,
-		logger:          logger,
+		store:              store,
+		envOrchestrator:    envOrchestrator,
+		ciOrchestrator:     ciOrchestrator,
+		deployOrchestrator: deployOrchestrator,
+		search:             search,
+		templates:          templates,
+		blueprints:         blueprints,
+		manifests:          manifests,
+		authz:              authorizer,
+		submissions:        submissions,
+		policy:             policy,
+		audit:              auditLog,
+		readiness:          readiness,
+		logLevel:           logLevel,
+		logger:             logger,
 	}
 }
 
+// log returns the request-scoped logger, carrying the request ID,
+// principal, route and, once known, service and environment.
+func (h *Handlers) log(r *http.Request) *zap.Logger {
+	return logging.FromContext(r.Context(), h.logger)
+}
+
 // --- Platform endpoints ---
 
+// Healthz is a liveness check: it never touches dependencies, so a
+// dependency outage cannot get the pod restarted.
 func (h *Handlers) Healthz(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -42,12 +99,22 @@ func (h *Handlers) Healthz(w http.ResponseWriter, _ *http.Request) {
 	})
 }
 
-func (h *Handlers) Readyz(w http.ResponseWriter, _ *http.Request) {
+// Readyz runs the dependency checks. It answers 503 when a critical
+// dependency is unavailable, so Kubernetes stops routing to the pod;
+// Healthz stays a pure liveness check.
+func (h *Handlers) Readyz(w http.ResponseWriter, r *http.Request) {
+
+	report := h.readiness.Run(r.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(map[string]string{
-		"status": "ready",
-	})
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
 }
 
 // --- Service registry endpoints ---
@@ -59,10 +126,60 @@ func (h *Handlers) CreateService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Every service belongs to a team: quotas are enforced per team,
+	// and only the team's members may register services into it.
+	if req.Team == "" {
+		http.Error(w, "team is required", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.store.GetTeam(req.Team)
+	if err != nil {
+		http.Error(w, "unknown team "+req.Team, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionRegisterService, authz.Resource{
+		Kind:    kindTeam,
+		Name:    team.Name,
+		Service: req.Name,
+		Team:    team.Name,
+		Members: team.Members,
+	}) {
+		return
+	}
+
+	if req.Blueprint != "" {
+		if _, err := h.blueprints.Get(req.Blueprint); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	// Re-registering an existing service updates it, which only its
+	// owner may do. New services default to being owned by their creator,
+	// recorded by principal ID since names are neither unique nor stable.
+	if _, err := h.store.Get(req.Name); err == nil {
+		if !h.authorize(w, r, authz.ActionUpdateService, h.serviceResource(kindService, req.Name, req.Name)) {
+			return
+		}
+	}
+
+	if req.Owner == authz.GroupOwnerPrefix {
+		http.Error(w, "owner group name is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.Owner == "" {
+		if p := auth.FromRequest(r); p != nil && p.Method != auth.MethodAnonymous {
+			req.Owner = p.ID()
+		}
+	}
+
 	service := NewService(req)
 	h.store.Put(service)
 
-	h.logger.Info("service registered",
+	h.log(r).Info("service registered",
 		zap.String("service_id", service.ID.String()),
 		zap.String("name", service.Name),
 		zap.String("owner", service.Owner),
@@ -73,8 +190,17 @@ func (h *Handlers) CreateService(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(service)
 }
 
-func (h *Handlers) ListServices(w http.ResponseWriter, _ *http.Request) {
-	services := h.store.List()
+// ListServices returns the services the caller may read.
+func (h *Handlers) ListServices(w http.ResponseWriter, r *http.Request) {
+	principal := auth.FromRequest(r)
+
+	services := make([]Service, 0)
+	for _, svc := range h.store.List() {
+		res := h.serviceResource(kindService, svc.Name, svc.Name)
+		if h.authz.Decide(principal, authz.ActionReadService, res).Allowed {
+			services = append(services, svc)
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -83,91 +209,327 @@ func (h *Handlers) ListServices(w http.ResponseWriter, _ *http.Request) {
 
 // --- Environment endpoints (Phase 5) ---
 
+// CreateEnvironmentRequest creates an ephemeral environment.
+//
+// When the service is registered and carries a .platform.yaml at
+// Revision, its environment defaults apply. PullRequest marks the
+// environment as a PR environment, subject to the manifest
+// pull_request trigger rules; the pull request must be open in the
+// service repository, and Branch, if set, must be its base branch.
+// Without a TTL from either, the platform default TTL applies.
+//
+// Cluster overrides the service's default cluster and placement rules.
+//
+// Blueprint selects the kind of environment. Without one, the
+// manifest's, then the service's, then the platform default applies.
+// A blueprint's default TTL applies before the platform default.
+//
+// Parameters are passed to the create template, over the manifest's
+// environment parameters; only those the blueprint and template allow
+// users to set are accepted (see catalog.AnnotationUserParameters).
 type CreateEnvironmentRequest struct {
-	Name    string `json:"name"`
-	Service string `json:"service"`
-	TTL     string `json:"ttl"`
+	Name        string            `json:"name"`
+	Service     string            `json:"service"`
+	Cluster     string            `json:"cluster"`
+	Blueprint   string            `json:"blueprint"`
+	TTL         string            `json:"ttl"`
+	Revision    string            `json:"revision"`
+	Branch      string            `json:"branch"`
+	PullRequest int               `json:"pull_request"`
+	Parameters  map[string]string `json:"parameters"`
 }
 
 func (h *Handlers) CreateEnvironment(w http.ResponseWriter, r *http.Request) {
-	h.logger.Info("CreateEnvironment called")
+	h.log(r).Info("CreateEnvironment called")
 
 	var req CreateEnvironmentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("failed to decode request", zap.Error(err))
+		h.log(r).Error("failed to decode request", zap.Error(err))
 		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
 		return
 	}
 
-	h.logger.Info("parsed request",
+	h.log(r).Info("parsed request",
 		zap.String("name", req.Name),
 		zap.String("service", req.Service),
 		zap.String("ttl", req.TTL),
 	)
 
+	if !h.authorize(w, r, authz.ActionCreateEnvironment, h.serviceResource(kindEnvironment, req.Name, req.Service)) {
+		return
+	}
+
+	if !h.checkEnvironmentName(w, r, req.Name) {
+		return
+	}
+
+	trigger := orchestrator.TriggerAPI
+	if req.PullRequest > 0 {
+		trigger = orchestrator.TriggerPR
+	}
+
+	//-----------------------------------------
+	// Repository manifest (registered services only)
+	//-----------------------------------------
+
+	var team string
+
+	service, err := h.store.Get(req.Service)
+	registered := err == nil
+
+	if registered {
+		team = service.Team
+
+		if req.Cluster == "" {
+			req.Cluster = service.Cluster
+		}
+
+		if t, err := h.store.GetTeam(team); err == nil && req.Cluster == "" {
+			req.Cluster = t.Cluster
+		}
+
+		ev := manifest.Event{
+			Name:        manifest.EventAPI,
+			Branch:      req.Branch,
+			Revision:    req.Revision,
+			PullRequest: req.PullRequest,
+		}
+		if trigger == orchestrator.TriggerPR {
+			ev.Name = manifest.EventPullRequest
+		}
+
+		ev, m, ok := h.resolveManifest(w, r, service, ev)
+		if !ok {
+			return
+		}
+
+		if m != nil {
+			if trigger == orchestrator.TriggerPR && !m.Allows(ev.Name, ev.Branch) {
+				http.Error(w,
+					"pull request environments not permitted by "+manifest.FileName+" trigger rules",
+					http.StatusUnprocessableEntity,
+				)
+				return
+			}
+
+			if req.TTL == "" {
+				req.TTL = m.Environments.TTL
+			}
+
+			if req.Blueprint == "" {
+				req.Blueprint = m.Environments.Blueprint
+			}
+
+			if len(m.Environments.Parameters) > 0 {
+				params := make(map[string]string, len(m.Environments.Parameters)+len(req.Parameters))
+				for k, v := range m.Environments.Parameters {
+					params[k] = v
+				}
+				for k, v := range req.Parameters {
+					params[k] = v
+				}
+				req.Parameters = params
+			}
+		}
+
+		if req.Blueprint == "" {
+			req.Blueprint = service.Blueprint
+		}
+	}
+
+	bp, err := h.blueprints.Get(req.Blueprint)
+	if err != nil {
+		h.log(r).Warn("unknown blueprint", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	policy := h.policy.Get()
+
+	if req.TTL == "" && bp.DefaultTTL > 0 {
+		req.TTL = bp.DefaultTTL.String()
+	}
+
+	if req.TTL == "" && policy.DefaultTTL > 0 {
+		req.TTL = policy.DefaultTTL.String()
+	}
+
 	ttl, err := time.ParseDuration(req.TTL)
 	if err != nil {
-		h.logger.Error("invalid ttl", zap.Error(err))
+		h.log(r).Error("invalid ttl", zap.Error(err))
 		http.Error(w, "invalid ttl", http.StatusBadRequest)
 		return
 	}
 
-	h.logger.Info("submitting environment to orchestrator")
+	if policy.MaxTTL > 0 && ttl > policy.MaxTTL {
+		h.log(r).Warn("ttl exceeds platform maximum",
+			zap.Duration("ttl", ttl),
+			zap.Duration("max_ttl", policy.MaxTTL),
+		)
+		problem.Write(w, http.StatusForbidden, fmt.Sprintf(
+			"ttl %s exceeds the platform maximum of %s",
+			ttl,
+			policy.MaxTTL,
+		))
+		return
+	}
 
-	if _, err := h.envOrchestrator.Create(r.Context(), orchestrator.EnvironmentSpec{
-		Name:    req.Name,
-		Service: req.Service,
-		TTL:     ttl,
-	}); err != nil {
-		h.logger.Error("failed to create environment", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	release, ok := h.acquireSubmission(w, r, req.Service)
+	if !ok {
 		return
 	}
+	defer release()
+
+	unreserve, ok := h.admitEnvironment(w, r, &orchestrator.Environment{
+		Spec: orchestrator.EnvironmentSpec{
+			Name:    req.Name,
+			Service: req.Service,
+			Team:    team,
+			TTL:     ttl,
+		},
+		CreateWorkflow: orchestrator.WorkflowReference{
+			SubmittedAt: time.Now(),
+		},
+	}, service, registered)
+	if !ok {
+		return
+	}
+	defer unreserve()
+
+	h.log(r).Info("submitting environment to orchestrator")
+
+	env, err := h.envOrchestrator.Create(r.Context(), orchestrator.EnvironmentSpec{
+		Name:       req.Name,
+		Service:    req.Service,
+		Team:       team,
+		Cluster:    req.Cluster,
+		Blueprint:  bp.Name,
+		TTL:        ttl,
+		Trigger:    trigger,
+		Parameters: req.Parameters,
+	})
+	if err != nil {
+		h.log(r).Error("failed to create environment", zap.Error(err))
 
-	h.logger.Info("environment creation accepted")
-	w.WriteHeader(http.StatusAccepted)
-}
+		status := http.StatusInternalServerError
+		if errors.Is(err, executor.ErrUnknownCluster) ||
+			errors.Is(err, catalog.ErrInvalidParameters) ||
+			errors.Is(err, blueprint.ErrNotFound) {
+			status = http.StatusUnprocessableEntity
+		}
 
-func (h *Handlers) DeleteEnvironment(w http.ResponseWriter, r *http.Request) {
-	// Expected path: /api/v1/environments/{name}
-	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 4 {
-		http.Error(w, "missing environment name", http.StatusBadRequest)
+		http.Error(w, err.Error(), status)
 		return
 	}
 
-	name := parts[len(parts)-1]
+	// Remember where the environment was created so that destroy
+	// and status reads follow it, even if routing changes later.
+	h.store.PutEnvironment(env)
 
-	ctx := r.Context()
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	h.log(r).Info("environment creation accepted",
+		zap.String("blueprint", env.Spec.Blueprint),
+		zap.String("cluster", env.Spec.Cluster),
+		zap.String("namespace", env.CreateWorkflow.Namespace),
+	)
 
-	/*
-		if _, err := h.envOrchestrator.Destroy(ctx, name); err != nil {
-			h.logger.Error("failed to delete environment", zap.Error(err))
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-	*/
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"name":      env.Spec.Name,
+		"service":   env.Spec.Service,
+		"cluster":   env.Spec.Cluster,
+		"blueprint": env.Spec.Blueprint,
+		"workflow":  ToWorkflowReferenceResponse(env.CreateWorkflow),
+	})
+}
+
+func (h *Handlers) DeleteEnvironment(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
 
 	env, err := h.store.GetEnvironment(name)
 	if err != nil {
-		h.logger.Error("environment not found", zap.Error(err))
+		h.log(r).Error("environment not found", zap.Error(err))
 		http.Error(w, "environment not found", http.StatusNotFound)
 		return
 	}
 
-	if _, err := h.envOrchestrator.Destroy(
-		ctx,
-		name,
-		env.Spec.Service, // <-- critical
-	); err != nil {
+	if !h.authorize(w, r, authz.ActionDestroyEnvironment, h.serviceResource(kindEnvironment, name, env.Spec.Service)) {
+		return
+	}
+
+	release, ok := h.acquireSubmission(w, r, env.Spec.Service)
+	if !ok {
+		return
+	}
+	defer release()
 
-		h.logger.Error("failed to delete environment", zap.Error(err))
+	// The recorded environment carries the service and namespace.
+	destroyRef, err := h.envOrchestrator.Destroy(r.Context(), env)
+	if err != nil {
+		h.log(r).Error("failed to delete environment", zap.Error(err))
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if _, err := h.store.UpdateEnvironment(name, func(env *orchestrator.Environment) {
+		env.DestroyWorkflow = destroyRef
+	}); err != nil {
+		h.log(r).Error("failed to record destroy workflow", zap.Error(err))
+		http.Error(w, "failed to record destroy workflow", http.StatusInternalServerError)
+		return
+	}
+
 	w.WriteHeader(http.StatusAccepted)
 }
+
+// checkEnvironmentName refuses names that are not DNS labels, that
+// are reserved namespaces, or that belong to a live environment.
+// Environments become namespaces of the same name, so any of these
+// would let a caller adopt, and later delete, a namespace they do not
+// own. A destroyed environment's name may be reused by those allowed
+// to create environments for its service.
+func (h *Handlers) checkEnvironmentName(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+) bool {
+
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		http.Error(w, "invalid environment name: "+strings.Join(errs, "; "), http.StatusBadRequest)
+		return false
+	}
+
+	if h.reservedNamespace(name) {
+		http.Error(w, "environment name "+name+" is a reserved namespace", http.StatusUnprocessableEntity)
+		return false
+	}
+
+	existing, err := h.store.GetEnvironment(name)
+	if err != nil {
+		return true
+	}
+
+	if existing.DestroyWorkflow == nil {
+		http.Error(w, "environment "+name+" already exists", http.StatusConflict)
+		return false
+	}
+
+	return h.authorize(w, r, authz.ActionCreateEnvironment, h.serviceResource(kindEnvironment, name, existing.Spec.Service))
+}
+
+// reservedNamespace reports whether name is reserved by the platform
+// configuration or is a team's namespace.
+func (h *Handlers) reservedNamespace(name string) bool {
+
+	if h.policy.Get().ReservedNamespaces[name] {
+		return true
+	}
+
+	for _, t := range h.store.ListTeams() {
+		if t.Namespace == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,14 +1,95 @@
 package api
 
-import "github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+import (
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+)
 
 func ToWorkflowReferenceResponse(
 	ref orchestrator.WorkflowReference,
 ) WorkflowReferenceResponse {
 	return WorkflowReferenceResponse{
 		Name:        ref.Name,
+		Cluster:     ref.Cluster,
 		Namespace:   ref.Namespace,
 		Template:    ref.Template,
 		SubmittedAt: ref.SubmittedAt,
 	}
 }
+
+func ToWorkflowSummaryResponse(
+	s orchestrator.WorkflowSummary,
+) WorkflowSummaryResponse {
+	return WorkflowSummaryResponse{
+		Name:        s.Name,
+		Cluster:     s.Cluster,
+		Namespace:   s.Namespace,
+		UID:         s.UID,
+		Type:        s.Type,
+		Service:     s.Service,
+		Environment: s.Environment,
+		Trigger:     s.Trigger,
+		Template:    s.Template,
+		Phase:       s.Phase,
+		Message:     s.Message,
+		SubmittedAt: s.SubmittedAt,
+		StartedAt:   s.StartedAt,
+		FinishedAt:  s.FinishedAt,
+	}
+}
+
+func ToDeploymentResponse(
+	d *orchestrator.Deployment,
+	phase wf.WorkflowPhase,
+) DeploymentResponse {
+	return DeploymentResponse{
+		Service:   d.Spec.Service,
+		Image:     d.Spec.Image,
+		Revision:  d.Spec.Revision,
+		Namespace: d.Namespace,
+		Phase:     string(phase),
+		Workflow:  ToWorkflowReferenceResponse(d.Workflow),
+	}
+}
+
+func ToBlueprintResponse(
+	b blueprint.Blueprint,
+	isDefault bool,
+) BlueprintResponse {
+
+	resp := BlueprintResponse{
+		Name:            b.Name,
+		Description:     b.Description,
+		Default:         isDefault,
+		CreateTemplate:  b.CreateTemplate,
+		DestroyTemplate: b.DestroyTemplate,
+		TTLTemplate:     b.TTLTemplate,
+		Parameters:      b.Parameters,
+		Outputs:         b.Outputs,
+		Terraform:       b.Terraform,
+	}
+
+	if b.DefaultTTL > 0 {
+		resp.DefaultTTL = b.DefaultTTL.String()
+	}
+	if resp.Outputs == nil {
+		resp.Outputs = []blueprint.Output{}
+	}
+
+	return resp
+}
+
+func ToTemplateResponse(
+	t catalog.Template,
+) TemplateResponse {
+	return TemplateResponse{
+		Name:       t.Name,
+		Namespace:  t.Namespace,
+		Purpose:    string(t.Purpose),
+		Language:   t.Language,
+		Parameters: t.Parameters,
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/quota"
 )
 
 // Service is the authoritative domain entity managed by the control plane.
@@ -12,8 +14,12 @@ type Service struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
 	Owner       string    `json:"owner"`
+	Team        string    `json:"team"`
+	Cluster     string    `json:"cluster"`
 	RepoURL     string    `json:"repo_url"`
+	Language    string    `json:"language"`
 	Environment string    `json:"environment"`
+	Blueprint   string    `json:"blueprint,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -23,8 +29,40 @@ func NewService(req CreateServiceRequest) Service {
 		ID:          uuid.New(),
 		Name:        req.Name,
 		Owner:       req.Owner,
+		Team:        req.Team,
+		Cluster:     req.Cluster,
 		RepoURL:     req.RepoURL,
+		Language:    req.Language,
 		Environment: req.Environment,
+		Blueprint:   req.Blueprint,
 		CreatedAt:   time.Now().UTC(),
 	}
 }
+
+// Team is a tenant: it owns services, and its members act on them.
+//
+// Namespace and Cluster are defaults for the team's services; service
+// routes and explicit clusters still win. Quotas bound the team's
+// combined usage across all of its services.
+type Team struct {
+	ID        uuid.UUID    `json:"id"`
+	Name      string       `json:"name"`
+	Members   []string     `json:"members"`
+	Namespace string       `json:"namespace"`
+	Cluster   string       `json:"cluster"`
+	Quotas    quota.Limits `json:"-"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// NewTeam constructs a Team from a validated API contract.
+func NewTeam(req CreateTeamRequest, limits quota.Limits) Team {
+	return Team{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Members:   req.Members,
+		Namespace: req.Namespace,
+		Cluster:   req.Cluster,
+		Quotas:    limits,
+		CreatedAt: time.Now().UTC(),
+	}
+}
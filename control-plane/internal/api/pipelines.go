@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/pipeline"
+)
+
+// CreatePipeline generates a workflow from declarative stages and
+// submits it through the executor.
+func (h *Handlers) CreatePipeline(w http.ResponseWriter, r *http.Request) {
+	var req CreatePipelineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	service, err := h.store.Get(req.Service)
+	if err != nil {
+		http.Error(w, "service not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionTriggerRun, h.serviceResource(kindService, service.Name, service.Name)) {
+		return
+	}
+
+	release, ok := h.acquireSubmission(w, r, service.Name)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !h.admitRun(w, r, service) {
+		return
+	}
+
+	run, err := h.ciOrchestrator.RunPipeline(
+		r.Context(),
+		orchestrator.RunSpec{
+			Service: service.Name,
+			Team:    service.Team,
+		},
+		&req.Pipeline,
+	)
+	if err != nil {
+		if errors.Is(err, pipeline.ErrInvalidSpec) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		h.log(r).Error("failed to submit pipeline",
+			zap.String("service", service.Name),
+			zap.String("pipeline", req.Pipeline.Name),
+			zap.Error(err),
+		)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.log(r).Info("pipeline accepted",
+		zap.String("service", service.Name),
+		zap.String("pipeline", req.Pipeline.Name),
+		zap.String("workflow", run.Workflow.Name),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"service":  service.Name,
+		"pipeline": req.Pipeline.Name,
+		"workflow": ToWorkflowReferenceResponse(run.Workflow),
+	})
+}
+
+// RenderPipeline returns the generated workflow YAML without
+// submitting anything.
+//
+// The output is what CreatePipeline would submit, minus the
+// namespace and platform labels added by the executor.
+func (h *Handlers) RenderPipeline(w http.ResponseWriter, r *http.Request) {
+	var req CreatePipelineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	workflow, err := pipeline.Generate(&req.Pipeline)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	out, err := pipeline.Render(workflow)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(out)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/quota"
+)
+
+// Policy is the platform-wide environment policy.
+//
+// DefaultTTL applies when neither the request nor the repository
+// manifest sets a TTL. MaxTTL caps every environment. DefaultQuotas
+// fill the quotas a team leaves unset. Zero disables each.
+//
+// ReservedNamespaces can never be environment names: environments
+// become namespaces of the same name, which destroy deletes.
+type Policy struct {
+	DefaultTTL         time.Duration
+	MaxTTL             time.Duration
+	DefaultQuotas      quota.Limits
+	ReservedNamespaces map[string]bool
+}
+
+// PolicySource holds the current Policy. It is replaced as a whole on
+// configuration reload, so a request sees one consistent policy.
+type PolicySource struct {
+	current atomic.Pointer[Policy]
+}
+
+func NewPolicySource(p Policy) *PolicySource {
+	s := &PolicySource{}
+	s.Set(p)
+	return s
+}
+
+func (s *PolicySource) Get() Policy {
+	return *s.current.Load()
+}
+
+func (s *PolicySource) Set(p Policy) {
+	s.current.Store(&p)
+}
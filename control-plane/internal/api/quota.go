@@ -0,0 +1,169 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/problem"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/quota"
+)
+
+// admitEnvironment reserves the environment's name and checks the
+// owning team's quotas before anything is submitted. The check and the
+// reservation are atomic, so concurrent creates cannot overrun a quota.
+// Services whose team cannot be found are refused rather than left
+// unlimited; unregistered services only reserve the name.
+//
+// The release func must be deferred on success, after the environment
+// is recorded.
+func (h *Handlers) admitEnvironment(
+	w http.ResponseWriter,
+	r *http.Request,
+	pending *orchestrator.Environment,
+	service Service,
+	registered bool,
+) (func(), bool) {
+
+	var admit func([]*orchestrator.Environment) error
+
+	if registered {
+		team, ok := h.owningTeam(w, r, service)
+		if !ok {
+			return nil, false
+		}
+
+		limits := h.teamLimits(team)
+		admit = func(envs []*orchestrator.Environment) error {
+			return quota.CheckEnvironment(
+				team.Name,
+				limits,
+				envs,
+				pending.Spec.TTL,
+				time.Now(),
+			)
+		}
+	}
+
+	release, err := h.store.ReserveEnvironment(pending, admit)
+	if errors.Is(err, ErrEnvironmentExists) {
+		http.Error(w, "environment "+pending.Spec.Name+" already exists", http.StatusConflict)
+		return nil, false
+	}
+	if !h.writeQuotaError(w, r, err) {
+		return nil, false
+	}
+
+	return release, true
+}
+
+// admitRun checks the owning team's concurrent run quota.
+func (h *Handlers) admitRun(
+	w http.ResponseWriter,
+	r *http.Request,
+	service Service,
+) bool {
+
+	team, ok := h.owningTeam(w, r, service)
+	if !ok {
+		return false
+	}
+
+	err := quota.CheckRun(
+		r.Context(),
+		h.search,
+		team.Name,
+		h.teamLimits(team),
+		h.store.ServicesOfTeam(team.Name),
+	)
+
+	return h.writeQuotaError(w, r, err)
+}
+
+// owningTeam looks up the team whose quotas apply to the service.
+func (h *Handlers) owningTeam(
+	w http.ResponseWriter,
+	r *http.Request,
+	service Service,
+) (Team, bool) {
+
+	team, err := h.store.GetTeam(service.Team)
+	if err != nil {
+		h.log(r).Warn("service has no registered team",
+			zap.String("service", service.Name),
+			zap.String("team", service.Team),
+		)
+		problem.Write(w, http.StatusForbidden, fmt.Sprintf(
+			"service %s has no registered team; quotas cannot be enforced",
+			service.Name,
+		))
+		return Team{}, false
+	}
+
+	return team, true
+}
+
+// teamLimits are the team's quotas, with unset quotas filled from
+// the platform defaults.
+func (h *Handlers) teamLimits(team Team) quota.Limits {
+	return team.Quotas.WithDefaults(h.policy.Get().DefaultQuotas)
+}
+
+// acquireSubmission reserves one of the service's concurrent
+// submission slots, so a runaway client cannot flood the workflow
+// controller even within its rate budget. It guards the submit calls
+// only; running workflows are bounded by team quotas. The release func
+// must be deferred on success.
+func (h *Handlers) acquireSubmission(
+	w http.ResponseWriter,
+	r *http.Request,
+	service string,
+) (func(), bool) {
+
+	release, ok := h.submissions.Acquire(service)
+	if ok {
+		return release, true
+	}
+
+	h.log(r).Warn("concurrent submission limit reached",
+		zap.String("service", service),
+		zap.Int("limit", h.submissions.Max()),
+	)
+
+	w.Header().Set("Retry-After", "1")
+	problem.Write(w, http.StatusTooManyRequests, fmt.Sprintf(
+		"service %s already has %d submissions in progress",
+		service,
+		h.submissions.Max(),
+	))
+	return nil, false
+}
+
+// writeQuotaError reports a quota violation (403/429) or a failure to
+// evaluate quotas (502), and returns whether the request may proceed.
+func (h *Handlers) writeQuotaError(w http.ResponseWriter, r *http.Request, err error) bool {
+
+	if err == nil {
+		return true
+	}
+
+	var v *quota.Violation
+	if errors.As(err, &v) {
+		h.log(r).Warn("quota exceeded",
+			zap.String("team", v.Team),
+			zap.String("quota", v.Quota),
+			zap.String("limit", v.Limit),
+			zap.String("used", v.Used),
+		)
+		problem.Write(w, v.HTTPStatus(), v.Error())
+		return false
+	}
+
+	h.log(r).Error("failed to evaluate quotas", zap.Error(err))
+	problem.Write(w, http.StatusBadGateway, err.Error())
+	return false
+}
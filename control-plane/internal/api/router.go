@@ -5,25 +5,51 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/audit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/health"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/manifest"
 	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/ratelimit"
 )
 
 // NewRouter wires the HTTP routes for the control-plane API.
+//
+// The mux is returned so that middleware can resolve route patterns.
 func NewRouter(
+	store *ServiceStore,
 	envOrchestrator orchestrator.EnvironmentOrchestrator,
+	ciOrchestrator orchestrator.CIOrchestrator,
+	deployOrchestrator orchestrator.DeploymentOrchestrator,
+	search orchestrator.WorkflowSearch,
+	templates *catalog.Catalog,
+	blueprints *blueprint.Registry,
+	manifests *manifest.Loader,
+	authorizer *authz.Authorizer,
+	submissions *ratelimit.Concurrency,
+	policy *PolicySource,
+	auditLog audit.Store,
+	readiness *health.Checker,
+	logLevel zap.AtomicLevel,
 	logger *zap.Logger,
-) http.Handler {
-	//store := NewServiceStore()
-
-	// Phase 5: Argo-backed environment orchestrator (namespace-scoped)
-	//envOrchestrator := orchestrator.NewArgoEnvironmentOrchestrator("argo")
-
-	//handlers := NewHandlers(store, envOrchestrator, logger)
-	store := NewServiceStore()
-
+) *http.ServeMux {
 	handlers := NewHandlers(
 		store,
 		envOrchestrator,
+		ciOrchestrator,
+		deployOrchestrator,
+		search,
+		templates,
+		blueprints,
+		manifests,
+		authorizer,
+		submissions,
+		policy,
+		auditLog,
+		readiness,
+		logLevel,
 		logger,
 	)
 
@@ -45,6 +71,106 @@ func NewRouter(
 		}
 	})
 
+	// API v1 — teams
+	mux.HandleFunc("/api/v1/teams", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handlers.CreateTeam(w, r)
+		case http.MethodGet:
+			handlers.ListTeams(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/v1/teams/{name}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handlers.GetTeam(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API v1 — CI runs
+	mux.HandleFunc("/api/v1/services/{name}/runs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handlers.CreateRun(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API v1 — generated pipelines
+	mux.HandleFunc("/api/v1/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handlers.CreatePipeline(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/v1/pipelines/render", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handlers.RenderPipeline(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API v1 — template catalog
+	mux.HandleFunc("/api/v1/templates", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handlers.ListTemplates(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API v1 — environment blueprints
+	mux.HandleFunc("/api/v1/blueprints", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handlers.ListBlueprints(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API v1 — workflow search
+	mux.HandleFunc("/api/v1/workflows", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handlers.ListWorkflows(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API v1 — audit log
+	mux.HandleFunc("/api/v1/audit", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handlers.ListAudit(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API v1 — admin
+	mux.HandleFunc("/api/v1/admin/log-level", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodPut:
+			handlers.LogLevel(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
 	// API v1 — environments
 	mux.HandleFunc("/api/v1/environments", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
@@ -55,8 +181,10 @@ func NewRouter(
 		}
 	})
 
-	mux.HandleFunc("/api/v1/environments/", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/api/v1/environments/{name}", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
+		case http.MethodGet:
+			handlers.GetEnvironment(w, r)
 		case http.MethodDelete:
 			handlers.DeleteEnvironment(w, r)
 		default:
@@ -64,5 +192,26 @@ func NewRouter(
 		}
 	})
 
+	mux.HandleFunc("/api/v1/environments/{name}/extend", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handlers.ExtendEnvironment(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// API v1 — deployments into environments
+	mux.HandleFunc("/api/v1/environments/{name}/deployments", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handlers.CreateDeployment(w, r)
+		case http.MethodGet:
+			handlers.ListDeployments(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
 	return mux
 }
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/contract"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/manifest"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/problem"
+)
+
+// CreateRun triggers a CI run for a registered service.
+//
+// The branch and pull request are checked against the repository
+// before its trigger rules apply (see resolveManifest).
+//
+// Template selection, in order of precedence:
+//  1. ci.template from the repository .platform.yaml at the revision
+//  2. the catalog template registered for the service language
+//
+// Parameters from the request override manifest parameters.
+func (h *Handlers) CreateRun(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	service, err := h.store.Get(name)
+	if err != nil {
+		http.Error(w, "service not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionTriggerRun, h.serviceResource(kindService, name, name)) {
+		return
+	}
+
+	release, ok := h.acquireSubmission(w, r, service.Name)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !h.admitRun(w, r, service) {
+		return
+	}
+
+	var req CreateRunRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Event == "" {
+		req.Event = manifest.EventAPI
+	}
+
+	switch req.Event {
+	case manifest.EventAPI, manifest.EventPullRequest:
+	case manifest.EventPush:
+		// Push runs are what trigger rules trust most; only the
+		// repository may report a push.
+		problem.Write(w, http.StatusForbidden, "event push is reserved for repository webhooks")
+		return
+	default:
+		http.Error(w, "unknown event "+req.Event, http.StatusBadRequest)
+		return
+	}
+
+	//-----------------------------------------
+	// Repository manifest (optional)
+	//-----------------------------------------
+
+	ev, m, ok := h.resolveManifest(w, r, service, manifest.Event{
+		Name:        req.Event,
+		Branch:      req.Branch,
+		Revision:    req.Revision,
+		PullRequest: req.PullRequest,
+	})
+	if !ok {
+		return
+	}
+
+	spec := orchestrator.RunSpec{
+		Service:    service.Name,
+		Team:       service.Team,
+		Language:   service.Language,
+		Revision:   ev.Revision,
+		Trigger:    ev.Name,
+		Parameters: map[string]string{},
+	}
+
+	if m != nil {
+		if !m.Allows(ev.Name, ev.Branch) {
+			http.Error(w,
+				"run not permitted by "+manifest.FileName+" trigger rules",
+				http.StatusUnprocessableEntity,
+			)
+			return
+		}
+
+		spec.Template = m.CI.Template
+		for k, v := range m.CI.Parameters {
+			spec.Parameters[k] = v
+		}
+	}
+
+	for k, v := range req.Parameters {
+		spec.Parameters[k] = v
+	}
+
+	run, err := h.ciOrchestrator.Run(r.Context(), spec)
+	if err != nil {
+		h.log(r).Error("failed to start run",
+			zap.String("service", service.Name),
+			zap.Error(err),
+		)
+
+		// Run parameters come from the caller, so a contract
+		// violation is theirs to fix.
+		status := http.StatusInternalServerError
+		if errors.Is(err, catalog.ErrTemplateNotFound) || errors.Is(err, contract.ErrViolation) {
+			status = http.StatusUnprocessableEntity
+		}
+
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	h.log(r).Info("run accepted",
+		zap.String("service", service.Name),
+		zap.String("workflow", run.Workflow.Name),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"service":  service.Name,
+		"revision": run.Spec.Revision,
+		"workflow": ToWorkflowReferenceResponse(run.Workflow),
+	})
+}
+
+// resolveManifest checks ev against the service repository and loads
+// the manifest at the resolved revision, writing the error response
+// itself when the handler must stop (see manifest.Loader.Resolve).
+//
+// The manifest is only required for repository events: their trigger
+// rules must be enforced. Calls through the API fall back to platform
+// defaults while the repository is unreachable, rather than failing.
+//
+// Returns ok=false when the handler must stop.
+func (h *Handlers) resolveManifest(
+	w http.ResponseWriter,
+	r *http.Request,
+	service Service,
+	ev manifest.Event,
+) (manifest.Event, *manifest.Manifest, bool) {
+
+	resolved, m, err := h.manifests.Resolve(r.Context(), service.RepoURL, ev)
+	switch {
+	case errors.Is(err, manifest.ErrInvalid), errors.Is(err, manifest.ErrUnverified):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return ev, nil, false
+
+	case err != nil && ev.Name == manifest.EventAPI:
+		h.log(r).Warn("repository unavailable, applying platform defaults",
+			zap.String("service", service.Name),
+			zap.String("revision", ev.Revision),
+			zap.Error(err),
+		)
+		if ev.Revision == "" {
+			ev.Revision = ev.Branch
+		}
+		return ev, nil, true
+
+	case err != nil:
+		h.log(r).Error("failed to load manifest",
+			zap.String("service", service.Name),
+			zap.String("revision", ev.Revision),
+			zap.Error(err),
+		)
+		http.Error(w, "failed to load "+manifest.FileName, http.StatusBadGateway)
+		return ev, nil, false
+	}
+
+	return resolved, m, true
+}
@@ -36,6 +36,7 @@ package api
 
 import (
 	"errors"
+	"sort"
 	"sync"
 
 	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
@@ -43,19 +44,114 @@ import (
 
 var ErrEnvironmentNotFound = errors.New("environment not found")
 var ErrServiceNotFound = errors.New("service not found")
+var ErrTeamNotFound = errors.New("team not found")
+var ErrEnvironmentExists = errors.New("environment already exists")
 
 type ServiceStore struct {
 	mu sync.RWMutex
 
+	teams        map[string]Team
 	services     map[string]Service
 	environments map[string]*orchestrator.Environment
+
+	// reservations are environments admitted but not yet submitted.
+	// They hold their name and count against their team's quotas.
+	reservations map[string]*orchestrator.Environment
 }
 
 func NewServiceStore() *ServiceStore {
 	return &ServiceStore{
+		teams:        make(map[string]Team),
 		services:     make(map[string]Service),
 		environments: make(map[string]*orchestrator.Environment),
+		reservations: make(map[string]*orchestrator.Environment),
+	}
+}
+
+//
+// -----------------------------
+// Team Methods
+// -----------------------------
+
+// PutTeam registers team, or replaces the team of the same name while
+// keeping its ID and creation time. It returns the team as stored.
+func (s *ServiceStore) PutTeam(team Team) Team {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.teams[team.Name]; ok {
+		team.ID = existing.ID
+		team.CreatedAt = existing.CreatedAt
+	}
+
+	s.teams[team.Name] = team
+
+	return team
+}
+
+func (s *ServiceStore) GetTeam(name string) (Team, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	team, ok := s.teams[name]
+	if !ok {
+		return Team{}, ErrTeamNotFound
+	}
+
+	return team, nil
+}
+
+func (s *ServiceStore) ListTeams() []Team {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Team, 0, len(s.teams))
+	for _, t := range s.teams {
+		out = append(out, t)
 	}
+
+	return out
+}
+
+// TeamNamespace implements orchestrator.TeamNamespaces.
+func (s *ServiceStore) TeamNamespace(team string) (string, bool) {
+	t, err := s.GetTeam(team)
+	if err != nil || t.Namespace == "" {
+		return "", false
+	}
+	return t.Namespace, true
+}
+
+// RegisteredNamespaces implements orchestrator.TeamNamespaces.
+func (s *ServiceStore) RegisteredNamespaces() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]string, 0, len(s.teams))
+	for _, t := range s.teams {
+		if t.Namespace != "" {
+			out = append(out, t.Namespace)
+		}
+	}
+
+	return out
+}
+
+// ServicesOfTeam returns the names of the team's services.
+func (s *ServiceStore) ServicesOfTeam(team string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]string, 0)
+	for _, svc := range s.services {
+		if svc.Team == team {
+			out = append(out, svc.Name)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
 }
 
 //
@@ -99,13 +195,17 @@ func (s *ServiceStore) List() []Service {
 // Environment Methods
 // -----------------------------
 
+// PutEnvironment records a copy of env, replacing any record of the
+// same name.
 func (s *ServiceStore) PutEnvironment(env *orchestrator.Environment) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.environments[env.Spec.Name] = env
+	s.environments[env.Spec.Name] = env.Clone()
 }
 
+// GetEnvironment returns a snapshot of the named environment. Changes
+// to it are not recorded; see UpdateEnvironment.
 func (s *ServiceStore) GetEnvironment(
 	name string,
 ) (*orchestrator.Environment, error) {
@@ -118,7 +218,118 @@ func (s *ServiceStore) GetEnvironment(
 		return nil, ErrEnvironmentNotFound
 	}
 
-	return env, nil
+	return env.Clone(), nil
+}
+
+// UpdateEnvironment applies update to the recorded environment under
+// the store lock, so that concurrent updates are neither lost nor seen
+// half-applied, and returns a snapshot of the result.
+func (s *ServiceStore) UpdateEnvironment(
+	name string,
+	update func(env *orchestrator.Environment),
+) (*orchestrator.Environment, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env, ok := s.environments[name]
+	if !ok {
+		return nil, ErrEnvironmentNotFound
+	}
+
+	update(env)
+
+	return env.Clone(), nil
+}
+
+// ListEnvironments returns snapshots of every recorded environment,
+// destroyed ones included, so that background readers such as the
+// metrics observer never race with handlers.
+func (s *ServiceStore) ListEnvironments() []*orchestrator.Environment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*orchestrator.Environment, 0, len(s.environments))
+	for _, env := range s.environments {
+		out = append(out, env.Clone())
+	}
+
+	return out
+}
+
+// EnvironmentsOfTeam returns snapshots of every recorded environment
+// of the team's services, destroyed ones included.
+func (s *ServiceStore) EnvironmentsOfTeam(team string) []*orchestrator.Environment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []*orchestrator.Environment
+	for _, env := range s.environments {
+		if svc, ok := s.services[env.Spec.Service]; ok && svc.Team == team {
+			out = append(out, env.Clone())
+		}
+	}
+
+	return out
+}
+
+// environmentsOfTeam is EnvironmentsOfTeam plus pending reservations.
+// The caller must hold s.mu.
+func (s *ServiceStore) environmentsOfTeam(team string) []*orchestrator.Environment {
+
+	var out []*orchestrator.Environment
+	for _, envs := range []map[string]*orchestrator.Environment{s.environments, s.reservations} {
+		for _, env := range envs {
+			if svc, ok := s.services[env.Spec.Service]; ok && svc.Team == team {
+				out = append(out, env.Clone())
+			}
+		}
+	}
+
+	return out
+}
+
+// ReserveEnvironment admits pending and holds its name until the
+// returned release func is called, which must happen once the
+// environment is recorded or its submission has failed.
+//
+// admit, if set, is called with the team's recorded and reserved
+// environments under the store lock, so concurrent creates cannot both
+// pass a quota with one slot left. It must not call back into the store.
+// A live environment or reservation of the same name fails with
+// ErrEnvironmentExists.
+func (s *ServiceStore) ReserveEnvironment(
+	pending *orchestrator.Environment,
+	admit func(envs []*orchestrator.Environment) error,
+) (func(), error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := pending.Spec.Name
+
+	if _, ok := s.reservations[name]; ok {
+		return nil, ErrEnvironmentExists
+	}
+	if env, ok := s.environments[name]; ok && env.DestroyWorkflow == nil {
+		return nil, ErrEnvironmentExists
+	}
+
+	if admit != nil {
+		team := s.services[pending.Spec.Service].Team
+		if err := admit(s.environmentsOfTeam(team)); err != nil {
+			return nil, err
+		}
+	}
+
+	s.reservations[name] = pending.Clone()
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.reservations, name)
+	}, nil
 }
 
 func (s *ServiceStore) DeleteEnvironment(name string) {
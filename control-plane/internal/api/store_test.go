@@ -0,0 +1,195 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/quota"
+)
+
+func TestUpdateEnvironmentConcurrentAppends(t *testing.T) {
+
+	store := NewServiceStore()
+	store.PutEnvironment(&orchestrator.Environment{
+		Spec: orchestrator.EnvironmentSpec{Name: "e1"},
+	})
+
+	const writers = 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			_, err := store.UpdateEnvironment("e1", func(env *orchestrator.Environment) {
+				env.Deployments = append(env.Deployments, &orchestrator.Deployment{})
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			if env, err := store.GetEnvironment("e1"); err == nil {
+				_ = len(env.Deployments)
+			}
+		}()
+	}
+	wg.Wait()
+
+	env, err := store.GetEnvironment("e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(env.Deployments); got != writers {
+		t.Fatalf("deployments = %d, want %d", got, writers)
+	}
+}
+
+func TestGetEnvironmentReturnsSnapshot(t *testing.T) {
+
+	store := NewServiceStore()
+	store.PutEnvironment(&orchestrator.Environment{
+		Spec: orchestrator.EnvironmentSpec{
+			Name:       "e1",
+			Parameters: map[string]string{"a": "1"},
+		},
+	})
+
+	env, _ := store.GetEnvironment("e1")
+	env.Spec.Parameters["a"] = "2"
+	env.Deployments = append(env.Deployments, &orchestrator.Deployment{})
+
+	again, _ := store.GetEnvironment("e1")
+	if again.Spec.Parameters["a"] != "1" || len(again.Deployments) != 0 {
+		t.Fatalf("store changed through a snapshot: %+v", again)
+	}
+}
+
+func TestUpdateEnvironmentNotFound(t *testing.T) {
+
+	_, err := NewServiceStore().UpdateEnvironment("missing", func(*orchestrator.Environment) {})
+	if err != ErrEnvironmentNotFound {
+		t.Fatalf("err = %v, want %v", err, ErrEnvironmentNotFound)
+	}
+}
+
+func TestReserveEnvironmentConcurrentQuota(t *testing.T) {
+
+	store := NewServiceStore()
+	store.Put(Service{Name: "svc", Team: "core"})
+
+	const (
+		creators = 20
+		max      = 3
+	)
+
+	admit := func(envs []*orchestrator.Environment) error {
+		return quota.CheckEnvironment(
+			"core",
+			quota.Limits{MaxEnvironments: max},
+			envs,
+			time.Hour,
+			time.Now(),
+		)
+	}
+
+	var admitted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < creators; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.ReserveEnvironment(&orchestrator.Environment{
+				Spec: orchestrator.EnvironmentSpec{
+					Name:    fmt.Sprintf("e%d", i),
+					Service: "svc",
+					TTL:     time.Hour,
+				},
+				CreateWorkflow: orchestrator.WorkflowReference{SubmittedAt: time.Now()},
+			}, admit)
+			if err == nil {
+				admitted.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := admitted.Load(); got != max {
+		t.Fatalf("admitted %d environments, want %d", got, max)
+	}
+}
+
+func TestPutTeamReplaceKeepsIdentity(t *testing.T) {
+
+	store := NewServiceStore()
+
+	first := store.PutTeam(NewTeam(CreateTeamRequest{Name: "payments", Members: []string{"alice"}}, quota.Limits{}))
+
+	replaced := store.PutTeam(NewTeam(CreateTeamRequest{Name: "payments", Members: []string{"bob"}}, quota.Limits{MaxEnvironments: 2}))
+
+	if replaced.ID != first.ID || !replaced.CreatedAt.Equal(first.CreatedAt) {
+		t.Fatalf("replaced team = %s at %s, want %s at %s", replaced.ID, replaced.CreatedAt, first.ID, first.CreatedAt)
+	}
+
+	got, err := store.GetTeam("payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != first.ID || got.Members[0] != "bob" || got.Quotas.MaxEnvironments != 2 {
+		t.Fatalf("stored team = %+v", got)
+	}
+}
+
+func TestReserveEnvironmentName(t *testing.T) {
+
+	store := NewServiceStore()
+	store.PutEnvironment(&orchestrator.Environment{
+		Spec: orchestrator.EnvironmentSpec{Name: "live"},
+	})
+	store.PutEnvironment(&orchestrator.Environment{
+		Spec:            orchestrator.EnvironmentSpec{Name: "destroyed"},
+		DestroyWorkflow: &orchestrator.WorkflowReference{Name: "env-destroy-1"},
+	})
+
+	pending := func(name string) *orchestrator.Environment {
+		return &orchestrator.Environment{Spec: orchestrator.EnvironmentSpec{Name: name}}
+	}
+
+	release, err := store.ReserveEnvironment(pending("new"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"live", ErrEnvironmentExists},
+		{"new", ErrEnvironmentExists},
+		{"destroyed", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.ReserveEnvironment(pending(tt.name), nil)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	release()
+
+	if _, err := store.ReserveEnvironment(pending("new"), nil); err != nil {
+		t.Fatalf("reserve after release: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/quota"
+)
+
+// CreateTeam registers a team, or replaces it if it exists; a replaced
+// team keeps its ID and creation time. Teams and their quotas are
+// managed by platform admins.
+func (h *Handlers) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	var req CreateTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	if req.Name == "" {
+		http.Error(w, "team name is required", http.StatusBadRequest)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionManageTeams, authz.Resource{Kind: kindTeam, Name: req.Name}) {
+		return
+	}
+
+	limits, err := toQuotaLimits(req.Quotas)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	team := h.store.PutTeam(NewTeam(req, limits))
+
+	h.log(r).Info("team registered",
+		zap.String("team", team.Name),
+		zap.Int("members", len(team.Members)),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(h.toTeamResponse(team))
+}
+
+// ListTeams returns the teams the caller may read.
+func (h *Handlers) ListTeams(w http.ResponseWriter, r *http.Request) {
+	principal := auth.FromRequest(r)
+
+	teams := h.store.ListTeams()
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+
+	out := make([]TeamResponse, 0, len(teams))
+	for _, t := range teams {
+		if h.authz.Decide(principal, authz.ActionReadTeam, teamResource(t)).Allowed {
+			out = append(out, h.toTeamResponse(t))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// GetTeam returns a team with its current quota usage.
+func (h *Handlers) GetTeam(w http.ResponseWriter, r *http.Request) {
+	team, err := h.store.GetTeam(r.PathValue("name"))
+	if err != nil {
+		http.Error(w, "team not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.authorize(w, r, authz.ActionReadTeam, teamResource(team)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(h.toTeamResponse(team))
+}
+
+//
+// ---- Helpers ----
+//
+
+// teamResource describes a team: its members may read it.
+func teamResource(t Team) authz.Resource {
+	return authz.Resource{
+		Kind:    kindTeam,
+		Name:    t.Name,
+		Team:    t.Name,
+		Members: t.Members,
+	}
+}
+
+func (h *Handlers) toTeamResponse(t Team) TeamResponse {
+
+	usage := quota.EnvironmentUsage(h.store.EnvironmentsOfTeam(t.Name), time.Now())
+
+	return TeamResponse{
+		ID:        t.ID.String(),
+		Name:      t.Name,
+		Members:   t.Members,
+		Namespace: t.Namespace,
+		Cluster:   t.Cluster,
+		Services:  h.store.ServicesOfTeam(t.Name),
+		Quotas:    toQuotasContract(h.teamLimits(t)),
+		Usage: TeamUsageResponse{
+			ActiveEnvironments:    usage.ActiveEnvironments,
+			EnvironmentHoursMonth: usage.EnvironmentHoursMonth,
+		},
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// toQuotaLimits parses quotas. Zero means unlimited; negative limits
+// are rejected rather than read as unlimited.
+func toQuotaLimits(c TeamQuotasContract) (quota.Limits, error) {
+
+	var negative []string
+	if c.MaxEnvironments < 0 {
+		negative = append(negative, "max_environments")
+	}
+	if c.MaxConcurrentRuns < 0 {
+		negative = append(negative, "max_concurrent_runs")
+	}
+	if c.MaxEnvironmentHoursPerMonth < 0 {
+		negative = append(negative, "max_environment_hours_per_month")
+	}
+
+	limits := quota.Limits{
+		MaxEnvironments:             c.MaxEnvironments,
+		MaxConcurrentRuns:           c.MaxConcurrentRuns,
+		MaxEnvironmentHoursPerMonth: c.MaxEnvironmentHoursPerMonth,
+	}
+
+	if c.MaxTTL != "" {
+		ttl, err := time.ParseDuration(c.MaxTTL)
+		if err != nil {
+			return quota.Limits{}, fmt.Errorf("max_ttl: %w", err)
+		}
+		if ttl < 0 {
+			negative = append(negative, "max_ttl")
+		}
+		limits.MaxTTL = ttl
+	}
+
+	if len(negative) > 0 {
+		return quota.Limits{}, fmt.Errorf("quotas must not be negative: %s", strings.Join(negative, ", "))
+	}
+
+	return limits, nil
+}
+
+func toQuotasContract(l quota.Limits) TeamQuotasContract {
+
+	c := TeamQuotasContract{
+		MaxEnvironments:             l.MaxEnvironments,
+		MaxConcurrentRuns:           l.MaxConcurrentRuns,
+		MaxEnvironmentHoursPerMonth: l.MaxEnvironmentHoursPerMonth,
+	}
+
+	if l.MaxTTL > 0 {
+		c.MaxTTL = l.MaxTTL.String()
+	}
+
+	return c
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/quota"
+)
+
+func TestToQuotaLimits(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		in      TeamQuotasContract
+		want    quota.Limits
+		wantErr string
+	}{
+		{
+			name: "all set",
+			in:   TeamQuotasContract{MaxEnvironments: 3, MaxTTL: "8h", MaxConcurrentRuns: 2, MaxEnvironmentHoursPerMonth: 100},
+			want: quota.Limits{MaxEnvironments: 3, MaxTTL: 8 * time.Hour, MaxConcurrentRuns: 2, MaxEnvironmentHoursPerMonth: 100},
+		},
+		{
+			name: "unset is unlimited",
+		},
+		{
+			name:    "bad ttl",
+			in:      TeamQuotasContract{MaxTTL: "eight hours"},
+			wantErr: "max_ttl",
+		},
+		{
+			name:    "negatives",
+			in:      TeamQuotasContract{MaxEnvironments: -1, MaxTTL: "-1h", MaxConcurrentRuns: -1, MaxEnvironmentHoursPerMonth: -0.5},
+			wantErr: "quotas must not be negative: max_environments, max_concurrent_runs, max_environment_hours_per_month, max_ttl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toQuotaLimits(tt.in)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("toQuotaLimits() err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Fatalf("toQuotaLimits() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
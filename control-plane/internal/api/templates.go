@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ListTemplates returns the WorkflowTemplates discovered by the catalog.
+//
+// Optional query filters:
+//
+//	?purpose=ci
+//	?language=node
+func (h *Handlers) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	purpose := r.URL.Query().Get("purpose")
+	language := r.URL.Query().Get("language")
+
+	out := make([]TemplateResponse, 0)
+	for _, t := range h.templates.List() {
+		if purpose != "" && string(t.Purpose) != purpose {
+			continue
+		}
+		if language != "" && t.Language != language {
+			continue
+		}
+		out = append(out, ToTemplateResponse(t))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"templates":    out,
+		"refreshed_at": h.templates.RefreshedAt(),
+	})
+}
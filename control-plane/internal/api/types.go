@@ -1,10 +1,94 @@
 package api
 
-import "time"
+import (
+	"time"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/audit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+)
 
 type WorkflowReferenceResponse struct {
 	Name        string    `json:"name"`
+	Cluster     string    `json:"cluster,omitempty"`
 	Namespace   string    `json:"namespace"`
 	Template    string    `json:"template"`
 	SubmittedAt time.Time `json:"submitted_at"`
 }
+
+type WorkflowSummaryResponse struct {
+	Name        string     `json:"name"`
+	Cluster     string     `json:"cluster,omitempty"`
+	Namespace   string     `json:"namespace"`
+	UID         string     `json:"uid"`
+	Type        string     `json:"type"`
+	Service     string     `json:"service"`
+	Environment string     `json:"environment,omitempty"`
+	Trigger     string     `json:"trigger,omitempty"`
+	Template    string     `json:"template"`
+	Phase       string     `json:"phase"`
+	Message     string     `json:"message,omitempty"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+type WorkflowListResponse struct {
+	Workflows []WorkflowSummaryResponse `json:"workflows"`
+	Continue  string                    `json:"continue,omitempty"`
+}
+
+type AuditListResponse struct {
+	Records []audit.Record `json:"records"`
+}
+
+type TeamResponse struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Members   []string           `json:"members"`
+	Namespace string             `json:"namespace,omitempty"`
+	Cluster   string             `json:"cluster,omitempty"`
+	Services  []string           `json:"services"`
+	Quotas    TeamQuotasContract `json:"quotas"`
+	Usage     TeamUsageResponse  `json:"usage"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type TeamUsageResponse struct {
+	ActiveEnvironments    int     `json:"active_environments"`
+	EnvironmentHoursMonth float64 `json:"environment_hours_this_month"`
+}
+
+// BlueprintResponse describes a kind of environment. Empty templates
+// resolve by purpose from the catalog.
+type BlueprintResponse struct {
+	Name            string               `json:"name"`
+	Description     string               `json:"description,omitempty"`
+	Default         bool                 `json:"default"`
+	CreateTemplate  string               `json:"create_template,omitempty"`
+	DestroyTemplate string               `json:"destroy_template,omitempty"`
+	TTLTemplate     string               `json:"ttl_template,omitempty"`
+	DefaultTTL      string               `json:"default_ttl,omitempty"`
+	Parameters      []string             `json:"parameters,omitempty"`
+	Outputs         []blueprint.Output   `json:"outputs"`
+	Terraform       *blueprint.Terraform `json:"terraform,omitempty"`
+}
+
+// DeploymentResponse is a deployment into an environment. Phase is
+// empty when the workflow can no longer be read.
+type DeploymentResponse struct {
+	Service   string                    `json:"service"`
+	Image     string                    `json:"image"`
+	Revision  string                    `json:"revision,omitempty"`
+	Namespace string                    `json:"namespace"`
+	Phase     string                    `json:"phase"`
+	Workflow  WorkflowReferenceResponse `json:"workflow"`
+}
+
+type TemplateResponse struct {
+	Name       string              `json:"name"`
+	Namespace  string              `json:"namespace"`
+	Purpose    string              `json:"purpose"`
+	Language   string              `json:"language,omitempty"`
+	Parameters []catalog.Parameter `json:"parameters"`
+}
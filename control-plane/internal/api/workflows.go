@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+)
+
+// ListWorkflows searches every workflow the platform submitted.
+//
+// Query parameters (all optional):
+//
+//	?service=payments
+//	?environment=pr-42
+//	?type=environment-create
+//	?trigger=pull_request
+//	?template=env-create-template
+//	?phase=Running,Failed
+//	?submitted_after=2025-01-01T00:00:00Z
+//	?submitted_before=2025-02-01T00:00:00Z
+//	?cluster=dev&namespace=argo
+//	?limit=50&continue=<token>
+//
+// When service is set and registered, the namespace defaults to the
+// one routed for the service's team; without a service, every routed
+// namespace is searched. Without cluster, every cluster is searched.
+func (h *Handlers) ListWorkflows(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	q := orchestrator.WorkflowQuery{
+		Service:     params.Get("service"),
+		Environment: params.Get("environment"),
+		Type:        params.Get("type"),
+		Trigger:     params.Get("trigger"),
+		Template:    params.Get("template"),
+		Cluster:     params.Get("cluster"),
+		Namespace:   params.Get("namespace"),
+		Continue:    params.Get("continue"),
+	}
+
+	if phases := params.Get("phase"); phases != "" {
+		q.Phases = strings.Split(phases, ",")
+	}
+
+	if q.Service != "" {
+		if service, err := h.store.Get(q.Service); err == nil {
+			q.Team = service.Team
+		}
+	}
+
+	// Without a service filter the search spans every service, so
+	// only callers allowed to read any service's workflows may run it.
+	if !h.authorize(w, r, authz.ActionReadWorkflows, h.serviceResource(kindWorkflow, q.Service, q.Service)) {
+		return
+	}
+
+	var err error
+
+	if q.SubmittedAfter, err = parseTimeParam(params.Get("submitted_after")); err != nil {
+		http.Error(w, "invalid submitted_after: expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+	if q.SubmittedBefore, err = parseTimeParam(params.Get("submitted_before")); err != nil {
+		http.Error(w, "invalid submitted_before: expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+
+	if raw := params.Get("limit"); raw != "" {
+		if q.Limit, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.search.Search(r.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, orchestrator.ErrInvalidQuery):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, executor.ErrUnknownCluster):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			h.log(r).Error("failed to search workflows", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		}
+		return
+	}
+
+	resp := WorkflowListResponse{
+		Workflows: make([]WorkflowSummaryResponse, 0, len(page.Items)),
+		Continue:  page.Continue,
+	}
+	for _, s := range page.Items {
+		resp.Workflows = append(resp.Workflows, ToWorkflowSummaryResponse(s))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func parseTimeParam(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
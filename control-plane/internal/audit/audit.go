@@ -0,0 +1,156 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Outcome classifies how an audited operation ended.
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "succeeded"
+	OutcomeDenied    Outcome = "denied"   // 401/403
+	OutcomeRejected  Outcome = "rejected" // other 4xx: validation, quota, rate limit
+	OutcomeFailed    Outcome = "failed"   // 5xx or execution-plane error
+)
+
+// WorkflowRef identifies a workflow submitted on behalf of a record.
+type WorkflowRef struct {
+	Name       string            `json:"name"`
+	UID        string            `json:"uid"`
+	Cluster    string            `json:"cluster,omitempty"`
+	Namespace  string            `json:"namespace"`
+	Template   string            `json:"template,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// Record is one audit entry. Records are append-only: they are never
+// updated or deleted once written.
+type Record struct {
+	ID            string          `json:"id"`
+	Time          time.Time       `json:"time"`
+	Principal     string          `json:"principal"`
+	PrincipalName string          `json:"principal_name,omitempty"`
+	Action        string          `json:"action"`
+	Method        string          `json:"method,omitempty"`
+	Path          string          `json:"path,omitempty"`
+	RequestID     string          `json:"request_id,omitempty"`
+	Service       string          `json:"service,omitempty"`
+	Environment   string          `json:"environment,omitempty"`
+	Parameters    json.RawMessage `json:"parameters,omitempty"`
+	Workflows     []WorkflowRef   `json:"workflows,omitempty"`
+	Outcome       Outcome         `json:"outcome"`
+	Status        int             `json:"status,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+// Sink receives records.
+type Sink interface {
+	Write(ctx context.Context, rec Record) error
+}
+
+// Query filters records. Empty fields match everything.
+type Query struct {
+	Principal   string
+	Action      string
+	Service     string
+	Environment string
+	Outcome     Outcome
+	Since       time.Time
+	Until       time.Time
+	Limit       int
+}
+
+// Store is a sink that can be queried. Query returns the newest
+// matching records first.
+type Store interface {
+	Sink
+	Query(ctx context.Context, q Query) ([]Record, error)
+}
+
+// Matches reports whether rec satisfies q's filters (not its limit).
+func (q Query) Matches(rec Record) bool {
+	switch {
+	case q.Principal != "" && rec.Principal != q.Principal && rec.PrincipalName != q.Principal:
+		return false
+	case q.Action != "" && rec.Action != q.Action:
+		return false
+	case q.Service != "" && rec.Service != q.Service:
+		return false
+	case q.Environment != "" && rec.Environment != q.Environment:
+		return false
+	case q.Outcome != "" && rec.Outcome != q.Outcome:
+		return false
+	case !q.Since.IsZero() && rec.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !rec.Time.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+//
+// ---- Request-scoped records ----
+//
+
+// entry is the record being assembled for one request. Handlers and
+// the executor fill it in; the middleware writes it once the response
+// is known.
+type entry struct {
+	mu  sync.Mutex
+	rec Record
+}
+
+type entryKey struct{}
+
+func withEntry(ctx context.Context, e *entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, e)
+}
+
+func entryFrom(ctx context.Context) *entry {
+	e, _ := ctx.Value(entryKey{}).(*entry)
+	return e
+}
+
+// Annotate sets the action and target of the request's record.
+// Empty values leave the record unchanged. It is a no-op outside
+// audited requests.
+func Annotate(ctx context.Context, action, service, environment string) {
+
+	e := entryFrom(ctx)
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if action != "" {
+		e.rec.Action = action
+	}
+	if service != "" {
+		e.rec.Service = service
+	}
+	if environment != "" {
+		e.rec.Environment = environment
+	}
+}
+
+// attachWorkflow adds a submitted workflow to the request's record and
+// reports whether there was one.
+func attachWorkflow(ctx context.Context, ref WorkflowRef) bool {
+
+	e := entryFrom(ctx)
+	if e == nil {
+		return false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.rec.Workflows = append(e.rec.Workflows, ref)
+	return true
+}
@@ -0,0 +1,181 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+)
+
+// ActionSubmitWorkflow is recorded for submissions made outside an
+// audited request.
+const ActionSubmitWorkflow = "workflow.submit"
+
+// recordedWorkflowParameters are the workflow parameters kept in audit
+// records: those the platform sets itself. Others come from callers,
+// manifests or blueprints and may carry credentials, as may tf_vars,
+// so they are recorded by name with their value redacted, like request
+// bodies (see recordedFields).
+var recordedWorkflowParameters = map[string]bool{
+	orchestrator.ParamEnvName:         true,
+	orchestrator.ParamService:         true,
+	orchestrator.ParamExpiresAt:       true,
+	orchestrator.ParamRevision:        true,
+	orchestrator.ParamImage:           true,
+	orchestrator.ParamNamespace:       true,
+	orchestrator.ParamTFModule:        true,
+	orchestrator.ParamTFModuleVersion: true,
+	orchestrator.ParamTFStateKey:      true,
+}
+
+// Executor records every workflow submitted through it.
+//
+// Inside an audited request the workflow is attached to that request's
+// record; otherwise (background work) a record of its own is written.
+// Only platform-set parameter values are recorded (see
+// recordedWorkflowParameters). Reads and cancellations pass through.
+type Executor struct {
+	executor.WorkflowExecutor
+
+	sink   Sink
+	logger *zap.Logger
+}
+
+func NewExecutor(
+	inner executor.WorkflowExecutor,
+	sink Sink,
+	logger *zap.Logger,
+) *Executor {
+	return &Executor{
+		WorkflowExecutor: inner,
+		sink:             sink,
+		logger:           logger,
+	}
+}
+
+func (e *Executor) SubmitFromTemplate(
+	ctx context.Context,
+	target executor.Target,
+	templateName string,
+	generateName string,
+	parameters map[string]string,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	created, err := e.WorkflowExecutor.SubmitFromTemplate(
+		ctx,
+		target,
+		templateName,
+		generateName,
+		parameters,
+		labels,
+	)
+
+	e.record(ctx, target, templateName, parameters, labels, created, err)
+
+	return created, err
+}
+
+func (e *Executor) SubmitWorkflow(
+	ctx context.Context,
+	target executor.Target,
+	workflow *wf.Workflow,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	created, err := e.WorkflowExecutor.SubmitWorkflow(ctx, target, workflow, labels)
+
+	var parameters map[string]string
+	if workflow != nil {
+		parameters = make(map[string]string, len(workflow.Spec.Arguments.Parameters))
+		for _, p := range workflow.Spec.Arguments.Parameters {
+			parameters[p.Name] = p.GetValue()
+		}
+	}
+
+	e.record(ctx, target, "", parameters, labels, created, err)
+
+	return created, err
+}
+
+func (e *Executor) record(
+	ctx context.Context,
+	target executor.Target,
+	templateName string,
+	parameters map[string]string,
+	labels map[string]string,
+	created *wf.Workflow,
+	err error,
+) {
+
+	ref := WorkflowRef{
+		Cluster:    target.Cluster,
+		Namespace:  target.Namespace,
+		Template:   templateName,
+		Parameters: recordedWorkflowParams(parameters),
+	}
+	if created != nil {
+		ref.Name = created.Name
+		ref.UID = string(created.UID)
+	}
+
+	// A failed submission fails the request, which records the error.
+	if attachWorkflow(ctx, ref) {
+		return
+	}
+
+	rec := Record{
+		ID:          uuid.NewString(),
+		Time:        time.Now().UTC(),
+		Principal:   "system",
+		Action:      ActionSubmitWorkflow,
+		Service:     labels[executor.LabelService],
+		Environment: labels[orchestrator.LabelEnvironment],
+		Workflows:   []WorkflowRef{ref},
+		Outcome:     OutcomeSucceeded,
+	}
+
+	if p := auth.PrincipalFrom(ctx); p != nil {
+		rec.Principal = p.ID()
+		rec.PrincipalName = p.Name
+	}
+
+	if err != nil {
+		rec.Outcome = OutcomeFailed
+		rec.Error = err.Error()
+	}
+
+	if werr := e.sink.Write(context.WithoutCancel(ctx), rec); werr != nil {
+		e.logger.Error("failed to write audit record",
+			zap.String("audit_id", rec.ID),
+			zap.String("action", rec.Action),
+			zap.Error(werr),
+		)
+	}
+}
+
+// recordedWorkflowParams copies parameters, redacting the values that
+// are not recorded.
+func recordedWorkflowParams(parameters map[string]string) map[string]string {
+
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(parameters))
+
+	for k, v := range parameters {
+		if !recordedWorkflowParameters[k] || len(v) > maxRecordedValue {
+			v = redactedValue
+		}
+		out[k] = v
+	}
+
+	return out
+}
@@ -0,0 +1,251 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/logging"
+)
+
+// maxRecordedBody bounds the request body read for record parameters.
+const maxRecordedBody = 64 << 10
+
+// maxRecordedValue bounds each parameter kept in a record.
+const maxRecordedValue = 1 << 10
+
+// recordedFields are the request fields kept in audit records: names,
+// references and settings that say what was asked for. Other fields,
+// such as blueprint or deployment parameters that may carry
+// credentials, are recorded by name with their value redacted.
+var recordedFields = map[string]bool{
+	"name":         true,
+	"service":      true,
+	"team":         true,
+	"owner":        true,
+	"members":      true,
+	"namespace":    true,
+	"cluster":      true,
+	"blueprint":    true,
+	"template":     true,
+	"ttl":          true,
+	"revision":     true,
+	"branch":       true,
+	"pull_request": true,
+	"event":        true,
+	"image":        true,
+	"language":     true,
+	"repo_url":     true,
+	"environment":  true,
+	"quotas":       true,
+	"level":        true,
+}
+
+// redactedValue replaces values that are not recorded.
+const redactedValue = "[redacted]"
+
+var redacted = json.RawMessage(`"` + redactedValue + `"`)
+
+// maxRecordedError bounds the response body kept as the record error.
+const maxRecordedError = 1 << 10
+
+// Middleware writes one record per mutating request (POST, PUT, PATCH,
+// DELETE), after the response is known.
+//
+// The request body's recordedFields are kept as the parameters, with
+// other fields redacted; bodies that are not JSON objects are not
+// recorded. Handlers name
+// the action and target with Annotate; workflows submitted while
+// serving the request are attached by the audited executor.
+//
+// It must run outside authentication and anything else that may
+// reject a request, so that rejections are audited too, with Identify
+// inside authentication to record the principal.
+func Middleware(sink Sink, logger *zap.Logger) func(http.Handler) http.Handler {
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if !mutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			e := &entry{rec: Record{
+				ID:        uuid.NewString(),
+				Time:      time.Now().UTC(),
+				Action:    r.Method + " " + r.URL.Path,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				RequestID: logging.RequestID(r.Context()),
+			}}
+
+			e.rec.Parameters = recordedParameters(readBody(r))
+
+			rw := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r.WithContext(withEntry(r.Context(), e)))
+
+			e.mu.Lock()
+			rec := e.rec
+			e.mu.Unlock()
+
+			rec.Status = rw.status
+			rec.Outcome = outcomeOf(rw.status)
+			if rw.status >= http.StatusBadRequest {
+				rec.Error = errorDetail(rw.body.Bytes())
+			}
+
+			// The response is already sent; a lost record can only be logged.
+			if err := sink.Write(context.WithoutCancel(r.Context()), rec); err != nil {
+				logging.FromContext(r.Context(), logger).Error("failed to write audit record",
+					zap.String("audit_id", rec.ID),
+					zap.String("action", rec.Action),
+					zap.Error(err),
+				)
+			}
+		})
+	}
+}
+
+// Identify records the authenticated principal on the request's audit
+// record. It must run inside the authentication middleware.
+func Identify(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if e := entryFrom(r.Context()); e != nil {
+			if p := auth.FromRequest(r); p != nil {
+				e.mu.Lock()
+				e.rec.Principal = p.ID()
+				e.rec.PrincipalName = p.Name
+				e.mu.Unlock()
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+//
+// ---- Helpers ----
+//
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// readBody reads up to maxRecordedBody bytes and restores r.Body so
+// handlers see the full body.
+func readBody(r *http.Request) []byte {
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(r.Body, maxRecordedBody+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+
+	if err != nil || len(head) > maxRecordedBody {
+		return nil
+	}
+
+	return head
+}
+
+// recordedParameters keeps the recordedFields of a JSON object body and
+// redacts the rest.
+func recordedParameters(body []byte) json.RawMessage {
+
+	var fields map[string]json.RawMessage
+	if len(body) == 0 || json.Unmarshal(body, &fields) != nil || len(fields) == 0 {
+		return nil
+	}
+
+	for k, v := range fields {
+		if !recordedFields[k] || len(v) > maxRecordedValue {
+			fields[k] = redacted
+		}
+	}
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+
+	return out
+}
+
+func outcomeOf(status int) Outcome {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return OutcomeDenied
+	case status >= http.StatusInternalServerError:
+		return OutcomeFailed
+	case status >= http.StatusBadRequest:
+		return OutcomeRejected
+	}
+	return OutcomeSucceeded
+}
+
+// errorDetail extracts the detail of a problem response, or the plain
+// text of an http.Error response.
+func errorDetail(body []byte) string {
+
+	var p struct {
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal(body, &p) == nil && p.Detail != "" {
+		return p.Detail
+	}
+
+	return strings.TrimSpace(string(body))
+}
+
+// recorder captures the status and the start of error responses.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.status >= http.StatusBadRequest && r.body.Len() < maxRecordedError {
+		r.body.Write(b[:min(len(b), maxRecordedError-r.body.Len())])
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+func TestRecordedParameters(t *testing.T) {
+
+	long := `"` + strings.Repeat("x", maxRecordedValue) + `"`
+
+	tests := []struct {
+		name string
+		body string
+		want map[string]interface{}
+	}{
+		{
+			name: "recorded fields",
+			body: `{"name":"pr-42","service":"payments","ttl":"4h","pull_request":42}`,
+			want: map[string]interface{}{"name": "pr-42", "service": "payments", "ttl": "4h", "pull_request": float64(42)},
+		},
+		{
+			name: "parameters redacted",
+			body: `{"name":"pr-42","parameters":{"db_password":"hunter2"}}`,
+			want: map[string]interface{}{"name": "pr-42", "parameters": redactedValue},
+		},
+		{
+			name: "unknown fields redacted",
+			body: `{"token":"secret"}`,
+			want: map[string]interface{}{"token": redactedValue},
+		},
+		{
+			name: "oversized values redacted",
+			body: `{"name":` + long + `}`,
+			want: map[string]interface{}{"name": redactedValue},
+		},
+		{
+			name: "not an object",
+			body: `["a"]`,
+		},
+		{
+			name: "not json",
+			body: `name=pr-42`,
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := recordedParameters([]byte(tt.body))
+
+			if tt.want == nil {
+				if raw != nil {
+					t.Fatalf("recordedParameters() = %s, want nil", raw)
+				}
+				return
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(raw, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("recordedParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordedWorkflowParams(t *testing.T) {
+
+	got := recordedWorkflowParams(map[string]string{
+		"env_name":    "pr-42",
+		"service":     "payments",
+		"tf_vars":     `{"db_password":"hunter2"}`,
+		"db_password": "hunter2",
+		"revision":    strings.Repeat("x", maxRecordedValue+1),
+	})
+
+	want := map[string]string{
+		"env_name":    "pr-42",
+		"service":     "payments",
+		"tf_vars":     redactedValue,
+		"db_password": redactedValue,
+		"revision":    redactedValue,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("recordedWorkflowParams() = %v, want %v", got, want)
+	}
+
+	if recordedWorkflowParams(nil) != nil {
+		t.Fatal("recordedWorkflowParams(nil) should be nil")
+	}
+}
+
+func TestMiddlewareRedactsSubmittedWorkflows(t *testing.T) {
+
+	store := NewMemoryStore(10)
+	audited := NewExecutor(&fakeExecutor{}, store, zap.NewNop())
+
+	handler := Middleware(store, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := audited.SubmitFromTemplate(
+			r.Context(),
+			executor.Target{Namespace: "argo"},
+			"env-create-template",
+			"env-create-",
+			map[string]string{"env_name": "pr-42", "db_password": "hunter2"},
+			nil,
+		)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	body := `{"name":"pr-42","parameters":{"db_password":"hunter2"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/environments", strings.NewReader(body))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	recs, _ := store.Query(context.Background(), Query{})
+	if len(recs) != 1 {
+		t.Fatalf("got %d records, want 1", len(recs))
+	}
+
+	rec := recs[0]
+
+	if rec.Outcome != OutcomeSucceeded || rec.Status != http.StatusCreated {
+		t.Fatalf("record = %+v", rec)
+	}
+	if strings.Contains(string(rec.Parameters), "hunter2") {
+		t.Fatalf("request parameters not redacted: %s", rec.Parameters)
+	}
+	if len(rec.Workflows) != 1 || rec.Workflows[0].Name != "env-create-abc" {
+		t.Fatalf("workflows = %+v", rec.Workflows)
+	}
+	if got := rec.Workflows[0].Parameters; got["env_name"] != "pr-42" || got["db_password"] != redactedValue {
+		t.Fatalf("workflow parameters = %v", got)
+	}
+}
+
+// ---- Helpers ----
+
+type fakeExecutor struct {
+	executor.WorkflowExecutor
+}
+
+func (f *fakeExecutor) SubmitFromTemplate(
+	_ context.Context,
+	_ executor.Target,
+	_ string,
+	generateName string,
+	_ map[string]string,
+	_ map[string]string,
+) (*wf.Workflow, error) {
+	return &wf.Workflow{ObjectMeta: metav1.ObjectMeta{Name: generateName + "abc"}}, nil
+}
@@ -0,0 +1,200 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// DefaultQueryLimit and MaxQueryLimit bound query results.
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
+)
+
+//
+// ---- JSONL file ----
+//
+
+// FileStore appends one JSON record per line to a file opened in
+// append-only mode, and answers queries by scanning it.
+type FileStore struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+func OpenFileStore(path string) (*FileStore, error) {
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+
+	return &FileStore{
+		path: path,
+		file: f,
+	}, nil
+}
+
+// Write appends rec and syncs, so an acknowledged record survives a crash.
+func (s *FileStore) Write(_ context.Context, rec Record) error {
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(line); err != nil {
+		return fmt.Errorf("write audit record: %w", err)
+	}
+
+	return s.file.Sync()
+}
+
+func (s *FileStore) Query(ctx context.Context, q Query) ([]Record, error) {
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+
+	limit := queryLimit(q)
+	var matched []Record
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// A torn final line from a crash must not hide the rest.
+			continue
+		}
+
+		if q.Matches(rec) {
+			matched = append(matched, rec)
+			if len(matched) > limit {
+				matched = matched[1:]
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+
+	return newestFirst(matched), nil
+}
+
+// Ping checks that records still reach the configured path: the open
+// file must not have been removed or replaced underneath the store.
+func (s *FileStore) Ping(_ context.Context) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	open, err := s.file.Stat()
+	if err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+
+	current, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+
+	if !os.SameFile(open, current) {
+		return fmt.Errorf("audit log %s was replaced; records go to the old file", s.path)
+	}
+
+	return nil
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+//
+// ---- In-memory ----
+//
+
+// MemoryStore keeps the most recent records in memory, for
+// deployments without an audit file. Records are lost on restart.
+type MemoryStore struct {
+	max int
+
+	mu      sync.RWMutex
+	records []Record
+}
+
+// NewMemoryStore retains up to max records.
+func NewMemoryStore(max int) *MemoryStore {
+	return &MemoryStore{max: max}
+}
+
+func (s *MemoryStore) Write(_ context.Context, rec Record) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = append(s.records, rec)
+	if s.max > 0 && len(s.records) > s.max {
+		s.records = s.records[len(s.records)-s.max:]
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Query(_ context.Context, q Query) ([]Record, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limit := queryLimit(q)
+	out := make([]Record, 0, limit)
+
+	for i := len(s.records) - 1; i >= 0 && len(out) < limit; i-- {
+		if q.Matches(s.records[i]) {
+			out = append(out, s.records[i])
+		}
+	}
+
+	return out, nil
+}
+
+//
+// ---- Helpers ----
+//
+
+func queryLimit(q Query) int {
+	switch {
+	case q.Limit <= 0:
+		return DefaultQueryLimit
+	case q.Limit > MaxQueryLimit:
+		return MaxQueryLimit
+	}
+	return q.Limit
+}
+
+func newestFirst(records []Record) []Record {
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	return records
+}
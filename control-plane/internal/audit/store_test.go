@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStoreQuery(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, service := range []string{"payments", "billing", "payments", "payments", "billing", "payments"} {
+		rec := Record{
+			ID:      string(rune('a' + i)),
+			Time:    start.Add(time.Duration(i) * time.Minute),
+			Action:  "environment.create",
+			Service: service,
+			Outcome: OutcomeSucceeded,
+		}
+		if err := s.Write(context.Background(), rec); err != nil {
+			t.Fatal(err)
+		}
+
+		// A torn line, as a crash mid-write leaves behind.
+		if i == 2 {
+			appendRaw(t, path, `{"id":"torn","time":"2026-03-01T00:0`+"\n")
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  string
+	}{
+		{"newest first", Query{}, "fedcba"},
+		{"keeps the newest limit", Query{Limit: 2}, "fe"},
+		{"filters before limiting", Query{Service: "payments", Limit: 3}, "fdc"},
+		{"since and until", Query{Since: start.Add(time.Minute), Until: start.Add(4 * time.Minute)}, "dcb"},
+		{"no match", Query{Service: "search"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs, err := s.Query(context.Background(), tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ids(recs); got != tt.want {
+				t.Fatalf("Query() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+
+	s := NewMemoryStore(3)
+
+	for i := 0; i < 5; i++ {
+		_ = s.Write(context.Background(), Record{ID: string(rune('a' + i)), Outcome: OutcomeSucceeded})
+	}
+
+	recs, _ := s.Query(context.Background(), Query{})
+	if got := ids(recs); got != "edc" {
+		t.Fatalf("Query() = %q, want %q", got, "edc")
+	}
+}
+
+// ---- Helpers ----
+
+func ids(recs []Record) string {
+	var s string
+	for _, rec := range recs {
+		s += rec.ID
+	}
+	return s
+}
+
+func appendRaw(t *testing.T, path, line string) {
+
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(line); err != nil {
+		t.Fatal(err)
+	}
+}
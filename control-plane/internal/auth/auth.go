@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrUnauthenticated is returned when credentials are missing or invalid.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authentication methods.
+const (
+	MethodToken     = "token"
+	MethodOIDC      = "oidc"
+	MethodAnonymous = "anonymous"
+)
+
+// Principal is the authenticated caller.
+//
+// Subject is stable and unique per method: the token name for static
+// tokens, the "sub" claim for OIDC. Name is for display and audit.
+type Principal struct {
+	Subject string   `json:"subject"`
+	Name    string   `json:"name,omitempty"`
+	Groups  []string `json:"groups,omitempty"`
+	Method  string   `json:"method"`
+}
+
+// Anonymous is the principal used when authentication is disabled.
+var Anonymous = &Principal{
+	Subject: "anonymous",
+	Method:  MethodAnonymous,
+}
+
+// ID identifies the principal across methods, e.g. "oidc:1234".
+func (p *Principal) ID() string {
+	return p.Method + ":" + p.Subject
+}
+
+// Authenticator verifies one kind of bearer credential.
+type Authenticator interface {
+
+	// Accepts reports whether the credential is in this
+	// authenticator's format, without verifying it.
+	Accepts(credential string) bool
+
+	// Authenticate verifies the credential.
+	// Failures wrap ErrUnauthenticated.
+	Authenticate(ctx context.Context, credential string) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal stored by the middleware, or nil.
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// FromRequest is PrincipalFrom for handlers.
+func FromRequest(r *http.Request) *Principal {
+	return PrincipalFrom(r.Context())
+}
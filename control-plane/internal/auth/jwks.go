@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// KeySource resolves JWT signing keys by key ID.
+type KeySource interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// jwk is a single JSON Web Key (RFC 7517). Only public RSA and EC
+// signing keys are used; anything else is ignored.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// parseJWKS decodes a JWKS document into public keys by kid.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("parse jwks key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+
+	switch k.Kty {
+
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("rsa exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode key component: %w", err)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+//
+// ---- Static JWKS (local file) ----
+//
+
+// StaticKeys serves keys loaded once, e.g. from a mounted file in
+// air-gapped clusters that cannot reach the issuer.
+type StaticKeys struct {
+	keys map[string]crypto.PublicKey
+}
+
+func LoadStaticKeys(path string) (*StaticKeys, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks file: %w", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StaticKeys{keys: keys}, nil
+}
+
+func (s *StaticKeys) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	return lookupKey(s.keys, kid)
+}
+
+//
+// ---- Remote JWKS ----
+//
+
+// RemoteKeys fetches a JWKS URL and caches the result.
+//
+// The set is refetched after refreshInterval, and early when an
+// unknown kid is seen (key rotation), at most once per minRefetch.
+type RemoteKeys struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+const (
+	jwksRefreshInterval = time.Hour
+	jwksMinRefetch      = 30 * time.Second
+)
+
+func NewRemoteKeys(url string, client *http.Client) *RemoteKeys {
+	return &RemoteKeys{
+		url:    url,
+		client: client,
+	}
+}
+
+func (r *RemoteKeys) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	age := time.Since(r.fetchedAt)
+
+	if r.keys == nil || age > jwksRefreshInterval {
+		if err := r.fetch(ctx); err != nil {
+			return nil, err
+		}
+	} else if _, ok := r.keys[kid]; !ok && age > jwksMinRefetch {
+		if err := r.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return lookupKey(r.keys, kid)
+}
+
+func (r *RemoteKeys) fetch(ctx context.Context) error {
+
+	data, err := getJSON(ctx, r.client, r.url)
+	if err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	r.keys = keys
+	r.fetchedAt = time.Now()
+
+	return nil
+}
+
+// lookupKey finds a key by kid. A token without kid is accepted only
+// when the set holds exactly one key.
+func lookupKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, error) {
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrUnauthenticated, kid)
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/logging"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/problem"
+)
+
+// Middleware authenticates every request except those to public paths.
+//
+// Credentials are read from "Authorization: Bearer <credential>" and
+// handed to the first authenticator that accepts their format.
+// The resulting Principal is stored in the request context.
+//
+// With no authenticators, every request runs as Anonymous; this is
+// only allowed when authentication is explicitly disabled.
+func Middleware(
+	authenticators []Authenticator,
+	publicPaths []string,
+	logger *zap.Logger,
+) func(http.Handler) http.Handler {
+
+	public := make(map[string]bool, len(publicPaths))
+	for _, p := range publicPaths {
+		public[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if public[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(authenticators) == 0 {
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), Anonymous)))
+				return
+			}
+
+			principal, err := authenticate(r, authenticators)
+			if err != nil {
+				logging.FromContext(r.Context(), logger).Info("authentication failed",
+					zap.String("path", r.URL.Path),
+					zap.String("remote_addr", r.RemoteAddr),
+					zap.Error(err),
+				)
+
+				w.Header().Set("WWW-Authenticate", `Bearer realm="control-plane"`)
+
+				detail := "authentication required"
+				if !errors.Is(err, errMissingCredentials) {
+					detail = "invalid credentials"
+				}
+
+				problem.Write(w, http.StatusUnauthorized, detail)
+				return
+			}
+
+			logging.AddFields(r.Context(), zap.String("principal", principal.ID()))
+
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+var errMissingCredentials = errors.New("missing bearer credentials")
+
+func authenticate(r *http.Request, authenticators []Authenticator) (*Principal, error) {
+
+	header := r.Header.Get("Authorization")
+
+	scheme, credential, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || credential == "" {
+		return nil, errMissingCredentials
+	}
+
+	credential = strings.TrimSpace(credential)
+
+	for _, a := range authenticators {
+		if a.Accepts(credential) {
+			return a.Authenticate(r.Context(), credential)
+		}
+	}
+
+	return nil, errors.New("no authenticator accepts the credential")
+}
@@ -0,0 +1,348 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OIDCConfig configures JWT validation for one issuer.
+//
+// Keys come from, in order of precedence:
+//  1. JWKSFile  (local file)
+//  2. JWKSURL   (fetched)
+//  3. the issuer's discovery document (jwks_uri)
+type OIDCConfig struct {
+	Issuer        string
+	Audience      string
+	JWKSURL       string
+	JWKSFile      string
+	UsernameClaim string
+	GroupsClaim   string
+}
+
+// OIDCAuthenticator validates OIDC ID/access tokens (JWTs).
+//
+// Supported algorithms: RS256/384/512, PS256/384/512, ES256/384/512.
+// "none" and HMAC algorithms are always rejected.
+type OIDCAuthenticator struct {
+	cfg  OIDCConfig
+	keys KeySource
+	now  func() time.Time
+}
+
+// clockSkew tolerated on exp/nbf/iat.
+const clockSkew = time.Minute
+
+// NewOIDCAuthenticator resolves the key source for cfg.
+//
+// Discovery happens here, so an unreachable issuer fails startup
+// rather than the first request.
+func NewOIDCAuthenticator(
+	ctx context.Context,
+	cfg OIDCConfig,
+	client *http.Client,
+) (*OIDCAuthenticator, error) {
+
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("oidc: issuer is required")
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "sub"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+
+	var keys KeySource
+
+	switch {
+	case cfg.JWKSFile != "":
+		static, err := LoadStaticKeys(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = static
+
+	case cfg.JWKSURL != "":
+		keys = NewRemoteKeys(cfg.JWKSURL, client)
+
+	default:
+		jwksURL, err := discoverJWKS(ctx, client, cfg.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		keys = NewRemoteKeys(jwksURL, client)
+	}
+
+	return &OIDCAuthenticator{
+		cfg:  cfg,
+		keys: keys,
+		now:  time.Now,
+	}, nil
+}
+
+func (a *OIDCAuthenticator) Accepts(credential string) bool {
+	return isJWT(credential)
+}
+
+func (a *OIDCAuthenticator) Authenticate(
+	ctx context.Context,
+	credential string,
+) (*Principal, error) {
+
+	claims, err := a.verify(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	}
+
+	p := &Principal{
+		Subject: sub,
+		Method:  MethodOIDC,
+	}
+
+	p.Name, _ = claims[a.cfg.UsernameClaim].(string)
+	if p.Name == "" {
+		p.Name = sub
+	}
+
+	switch groups := claims[a.cfg.GroupsClaim].(type) {
+	case []interface{}:
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				p.Groups = append(p.Groups, s)
+			}
+		}
+	case string:
+		p.Groups = []string{groups}
+	}
+
+	return p, nil
+}
+
+//
+// ---- JWT verification ----
+//
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (a *OIDCAuthenticator) verify(
+	ctx context.Context,
+	token string,
+) (map[string]interface{}, error) {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrUnauthenticated)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrUnauthenticated)
+	}
+
+	key, err := a.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if err := a.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (a *OIDCAuthenticator) validateClaims(claims map[string]interface{}) error {
+
+	now := a.now()
+
+	if iss, _ := claims["iss"].(string); iss != a.cfg.Issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrUnauthenticated, iss)
+	}
+
+	if a.cfg.Audience != "" && !hasAudience(claims["aud"], a.cfg.Audience) {
+		return fmt.Errorf("%w: token not issued for audience %q", ErrUnauthenticated, a.cfg.Audience)
+	}
+
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return fmt.Errorf("%w: token has no expiry", ErrUnauthenticated)
+	}
+	if now.After(exp.Add(clockSkew)) {
+		return fmt.Errorf("%w: token expired", ErrUnauthenticated)
+	}
+
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(clockSkew).Before(nbf) {
+		return fmt.Errorf("%w: token not yet valid", ErrUnauthenticated)
+	}
+
+	if iat, ok := numericDate(claims["iat"]); ok && now.Add(clockSkew).Before(iat) {
+		return fmt.Errorf("%w: token issued in the future", ErrUnauthenticated)
+	}
+
+	return nil
+}
+
+func verifySignature(
+	alg string,
+	key crypto.PublicKey,
+	signingInput string,
+	signature []byte,
+) error {
+
+	unsupported := fmt.Errorf("%w: unsupported algorithm %q", ErrUnauthenticated, alg)
+
+	if len(alg) != 5 {
+		return unsupported
+	}
+
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return unsupported
+	}
+
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	invalid := fmt.Errorf("%w: invalid signature", ErrUnauthenticated)
+
+	switch alg[:2] {
+
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(pub, hash, digest, signature) != nil {
+			return invalid
+		}
+
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPSS(pub, hash, digest, signature, nil) != nil {
+			return invalid
+		}
+
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return invalid
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return invalid
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return invalid
+		}
+
+	default:
+		return unsupported
+	}
+
+	return nil
+}
+
+//
+// ---- Helpers ----
+//
+
+func discoverJWKS(ctx context.Context, client *http.Client, issuer string) (string, error) {
+
+	data, err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration")
+	if err != nil {
+		return "", fmt.Errorf("oidc discovery: %w", err)
+	}
+
+	var doc struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("oidc discovery: %w", err)
+	}
+	if doc.JWKSURI == "" {
+		return "", fmt.Errorf("oidc discovery: issuer %s publishes no jwks_uri", issuer)
+	}
+
+	return doc.JWKSURI, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed token", ErrUnauthenticated)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: malformed token", ErrUnauthenticated)
+	}
+
+	return nil
+}
+
+func hasAudience(aud interface{}, want string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == want
+	case []interface{}:
+		for _, a := range v {
+			if a == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func numericDate(v interface{}) (time.Time, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+// isJWT reports whether the credential has the three-segment JWS
+// compact shape.
+func isJWT(credential string) bool {
+	return strings.Count(credential, ".") == 2
+}
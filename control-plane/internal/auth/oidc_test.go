@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testIssuer = "https://issuer.example.com"
+
+func TestOIDCAuthenticate(t *testing.T) {
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+
+	a := &OIDCAuthenticator{
+		cfg: OIDCConfig{
+			Issuer:        testIssuer,
+			Audience:      "control-plane",
+			UsernameClaim: "email",
+			GroupsClaim:   "groups",
+		},
+		keys: &StaticKeys{keys: map[string]crypto.PublicKey{
+			"rsa": &rsaKey.PublicKey,
+			"ec":  &ecKey.PublicKey,
+		}},
+		now: func() time.Time { return now },
+	}
+
+	claims := func(override map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":    testIssuer,
+			"aud":    "control-plane",
+			"sub":    "u-123",
+			"email":  "alice@example.com",
+			"groups": []string{"platform", "payments"},
+			"iat":    now.Unix(),
+			"exp":    now.Add(time.Hour).Unix(),
+		}
+		for k, v := range override {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{
+			name:  "RS256",
+			token: signRS256(t, rsaKey, "rsa", claims(nil)),
+		},
+		{
+			name:  "ES256",
+			token: signES256(t, ecKey, "ec", claims(nil)),
+		},
+		{
+			name:  "audience list",
+			token: signRS256(t, rsaKey, "rsa", claims(map[string]interface{}{"aud": []string{"other", "control-plane"}})),
+		},
+		{
+			name:  "expired within skew",
+			token: signRS256(t, rsaKey, "rsa", claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()})),
+		},
+		{
+			name:    "expired",
+			token:   signRS256(t, rsaKey, "rsa", claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+			wantErr: "token expired",
+		},
+		{
+			name:    "no expiry",
+			token:   signRS256(t, rsaKey, "rsa", claims(map[string]interface{}{"exp": nil})),
+			wantErr: "no expiry",
+		},
+		{
+			name:    "not yet valid",
+			token:   signRS256(t, rsaKey, "rsa", claims(map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})),
+			wantErr: "not yet valid",
+		},
+		{
+			name:    "wrong issuer",
+			token:   signRS256(t, rsaKey, "rsa", claims(map[string]interface{}{"iss": "https://evil.example.com"})),
+			wantErr: "unexpected issuer",
+		},
+		{
+			name:    "wrong audience",
+			token:   signRS256(t, rsaKey, "rsa", claims(map[string]interface{}{"aud": "other"})),
+			wantErr: "audience",
+		},
+		{
+			name:    "no subject",
+			token:   signRS256(t, rsaKey, "rsa", claims(map[string]interface{}{"sub": nil})),
+			wantErr: "no subject",
+		},
+		{
+			name:    "signed by unknown key",
+			token:   signRS256(t, otherKey, "rsa", claims(nil)),
+			wantErr: "invalid signature",
+		},
+		{
+			name:    "unknown kid",
+			token:   signRS256(t, rsaKey, "rotated", claims(nil)),
+			wantErr: "unknown signing key",
+		},
+		{
+			name:    "RSA key used as EC",
+			token:   signRS256(t, rsaKey, "ec", claims(nil)),
+			wantErr: "invalid signature",
+		},
+		{
+			name:    "alg none",
+			token:   encodeSegment(t, map[string]string{"alg": "none", "kid": "rsa"}) + "." + encodeSegment(t, claims(nil)) + ".",
+			wantErr: "unsupported algorithm",
+		},
+		{
+			name:    "alg HS256",
+			token:   withHeader(t, signRS256(t, rsaKey, "rsa", claims(nil)), map[string]string{"alg": "HS256", "kid": "rsa"}),
+			wantErr: "unsupported algorithm",
+		},
+		{
+			name:    "tampered claims",
+			token:   withClaims(t, signRS256(t, rsaKey, "rsa", claims(nil)), claims(map[string]interface{}{"sub": "admin"})),
+			wantErr: "invalid signature",
+		},
+		{
+			name:    "malformed",
+			token:   "a.b",
+			wantErr: "malformed token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Authenticate(context.Background(), tt.token)
+
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrUnauthenticated) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Authenticate() err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Authenticate() = %v", err)
+			}
+			if p.Subject != "u-123" || p.Name != "alice@example.com" || p.Method != MethodOIDC {
+				t.Fatalf("principal = %+v", p)
+			}
+			if strings.Join(p.Groups, ",") != "platform,payments" {
+				t.Fatalf("groups = %v", p.Groups)
+			}
+		})
+	}
+}
+
+func TestLookupKey(t *testing.T) {
+
+	one := map[string]crypto.PublicKey{"a": "key-a"}
+	two := map[string]crypto.PublicKey{"a": "key-a", "b": "key-b"}
+
+	tests := []struct {
+		name string
+		keys map[string]crypto.PublicKey
+		kid  string
+		want crypto.PublicKey
+	}{
+		{"by kid", two, "b", "key-b"},
+		{"no kid with a single key", one, "", "key-a"},
+		{"no kid with several keys", two, "", nil},
+		{"unknown kid", one, "z", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lookupKey(tt.keys, tt.kid)
+
+			if tt.want == nil {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Fatalf("lookupKey() err = %v, want ErrUnauthenticated", err)
+				}
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Fatalf("lookupKey() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// ---- Helpers ----
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims interface{}) string {
+
+	t.Helper()
+
+	input := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims interface{}) string {
+
+	t.Helper()
+
+	input := encodeSegment(t, map[string]string{"alg": "ES256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// withHeader swaps the header of a signed token, keeping its signature.
+func withHeader(t *testing.T, token string, header interface{}) string {
+	parts := strings.Split(token, ".")
+	return encodeSegment(t, header) + "." + parts[1] + "." + parts[2]
+}
+
+// withClaims swaps the claims of a signed token, keeping its signature.
+func withClaims(t *testing.T, token string, claims interface{}) string {
+	parts := strings.Split(token, ".")
+	return parts[0] + "." + encodeSegment(t, claims) + "." + parts[2]
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// StaticToken is one entry in the token file.
+//
+// Only the SHA-256 of the token is stored:
+//
+//	tokens:
+//	  - name: release-bot
+//	    sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	    groups: [platform-admins]
+//
+// Generate a hash with:
+//
+//	printf %s "$TOKEN" | sha256sum
+type StaticToken struct {
+	Name   string   `json:"name"`
+	SHA256 string   `json:"sha256"`
+	Groups []string `json:"groups"`
+}
+
+type tokenFile struct {
+	Tokens []StaticToken `json:"tokens"`
+}
+
+// TokenAuthenticator verifies static API tokens against hashes.
+type TokenAuthenticator struct {
+	tokens []staticToken
+}
+
+type staticToken struct {
+	StaticToken
+	hash []byte
+}
+
+func NewTokenAuthenticator(tokens []StaticToken) (*TokenAuthenticator, error) {
+
+	a := &TokenAuthenticator{}
+	names := make(map[string]bool, len(tokens))
+
+	for _, t := range tokens {
+		if t.Name == "" {
+			return nil, fmt.Errorf("static token without name")
+		}
+		if names[t.Name] {
+			return nil, fmt.Errorf("static token %s defined twice", t.Name)
+		}
+		names[t.Name] = true
+
+		hash, err := hex.DecodeString(strings.ToLower(t.SHA256))
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("static token %s: sha256 must be 64 hex characters", t.Name)
+		}
+
+		a.tokens = append(a.tokens, staticToken{StaticToken: t, hash: hash})
+	}
+
+	return a, nil
+}
+
+// LoadTokenAuthenticator reads the token file, typically a mounted Secret.
+func LoadTokenAuthenticator(path string) (*TokenAuthenticator, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read token file: %w", err)
+	}
+
+	var f tokenFile
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("parse token file %s: %w", path, err)
+	}
+
+	return NewTokenAuthenticator(f.Tokens)
+}
+
+// Accepts any credential that is not shaped like a JWT.
+func (a *TokenAuthenticator) Accepts(credential string) bool {
+	return !isJWT(credential)
+}
+
+// Authenticate compares the token's hash against every entry in
+// constant time, so timing reveals neither which entry nor how much
+// of it matched.
+func (a *TokenAuthenticator) Authenticate(
+	_ context.Context,
+	credential string,
+) (*Principal, error) {
+
+	sum := sha256.Sum256([]byte(credential))
+
+	var match *staticToken
+	for i := range a.tokens {
+		if subtle.ConstantTimeCompare(sum[:], a.tokens[i].hash) == 1 {
+			match = &a.tokens[i]
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("%w: unknown API token", ErrUnauthenticated)
+	}
+
+	return &Principal{
+		Subject: match.Name,
+		Name:    match.Name,
+		Groups:  match.Groups,
+		Method:  MethodToken,
+	}, nil
+}
@@ -0,0 +1,297 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/logging"
+)
+
+// Action is something a principal does to a service or its environments.
+type Action string
+
+const (
+	ActionReadService        Action = "service.read"
+	ActionRegisterService    Action = "service.register"
+	ActionUpdateService      Action = "service.update"
+	ActionTriggerRun         Action = "run.trigger"
+	ActionReadEnvironment    Action = "environment.read"
+	ActionCreateEnvironment  Action = "environment.create"
+	ActionExtendEnvironment  Action = "environment.extend"
+	ActionDestroyEnvironment Action = "environment.destroy"
+	ActionDeploy             Action = "environment.deploy"
+	ActionReadWorkflows      Action = "workflow.read"
+	ActionReadTeam           Action = "team.read"
+	ActionManageTeams        Action = "team.manage"
+	ActionReadAudit          Action = "audit.read"
+	ActionManageLogging      Action = "logging.manage"
+)
+
+// ReadPolicy controls who may read services, environments, workflows
+// and teams.
+type ReadPolicy string
+
+const (
+	// ReadAuthenticated lets every authenticated principal read everything.
+	ReadAuthenticated ReadPolicy = "authenticated"
+
+	// ReadOwner restricts reads to owners and admins, like writes.
+	ReadOwner ReadPolicy = "owner"
+)
+
+// GroupOwnerPrefix marks an owner as a group, e.g. "group:payments".
+// Owners without it are users, so a group claim can never pass for a
+// user of the same name, nor a user for a group.
+const GroupOwnerPrefix = "group:"
+
+// Resource is what an action targets.
+//
+// Owner is the owning service's Service.Owner: a user, matched against
+// the principal's ID, subject or name, or a group written with the
+// GroupOwnerPrefix, matched against its group claims only. Members of
+// the service's team are owners too. With neither, only admins may act.
+type Resource struct {
+	Kind    string
+	Name    string
+	Service string
+	Owner   string
+	Team    string
+	Members []string
+}
+
+// Decision is the outcome of a policy check, with a human-readable reason.
+type Decision struct {
+	Allowed bool
+	Reason  string
+}
+
+// Config configures the policy.
+type Config struct {
+	AdminUsers     []string
+	AdminGroups    []string
+	Read           ReadPolicy
+	AllowAnonymous bool
+}
+
+// Validate reports whether Update would accept cfg.
+func (cfg Config) Validate() error {
+
+	switch cfg.Read {
+	case "", ReadAuthenticated, ReadOwner:
+		return nil
+	}
+
+	return fmt.Errorf("unknown read policy %q (want %s or %s)", cfg.Read, ReadAuthenticated, ReadOwner)
+}
+
+// Authorizer is the ownership policy:
+//
+//	admins       anything
+//	owner        anything on their services and its environments
+//	others       reads, when Read is ReadAuthenticated
+//
+// Every decision made through Authorize is logged with its reason.
+// The policy can be replaced at runtime with Update.
+type Authorizer struct {
+	policy atomic.Pointer[policy]
+	logger *zap.Logger
+}
+
+type policy struct {
+	adminUsers     map[string]bool
+	adminGroups    map[string]bool
+	read           ReadPolicy
+	allowAnonymous bool
+}
+
+func New(cfg Config, logger *zap.Logger) (*Authorizer, error) {
+
+	a := &Authorizer{logger: logger}
+
+	if err := a.Update(cfg); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Update replaces the policy. Decisions in progress finish with the
+// previous one.
+func (a *Authorizer) Update(cfg Config) error {
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.Read == "" {
+		cfg.Read = ReadAuthenticated
+	}
+
+	a.policy.Store(&policy{
+		adminUsers:     toSet(cfg.AdminUsers),
+		adminGroups:    toSet(cfg.AdminGroups),
+		read:           cfg.Read,
+		allowAnonymous: cfg.AllowAnonymous,
+	})
+
+	return nil
+}
+
+// Authorize decides and logs, with the request-scoped logger of ctx.
+func (a *Authorizer) Authorize(
+	ctx context.Context,
+	p *auth.Principal,
+	action Action,
+	res Resource,
+) Decision {
+
+	d := a.Decide(p, action, res)
+
+	fields := []zap.Field{
+		zap.Bool("allowed", d.Allowed),
+		zap.String("reason", d.Reason),
+		zap.String("action", string(action)),
+		zap.String("resource_kind", res.Kind),
+		zap.String("resource", res.Name),
+	}
+
+	// The request logger already carries service and principal.
+	if logging.RequestID(ctx) == "" {
+		fields = append(fields, zap.String("service", res.Service))
+		if p != nil {
+			fields = append(fields, zap.String("principal", p.ID()))
+		}
+	}
+
+	logger := logging.FromContext(ctx, a.logger)
+
+	if d.Allowed {
+		logger.Info("authorization allowed", fields...)
+	} else {
+		logger.Warn("authorization denied", fields...)
+	}
+
+	return d
+}
+
+// Decide evaluates the policy without logging, e.g. to filter lists.
+func (a *Authorizer) Decide(
+	p *auth.Principal,
+	action Action,
+	res Resource,
+) Decision {
+
+	pol := a.policy.Load()
+
+	switch {
+	case p == nil:
+		return deny("no authenticated principal")
+
+	case p.Method == auth.MethodAnonymous:
+		if pol.allowAnonymous {
+			return allow("authentication disabled")
+		}
+		return deny("anonymous access is not permitted")
+
+	case pol.isAdmin(p):
+		return allow("platform admin")
+
+	case isRead(action) && pol.read == ReadAuthenticated:
+		return allow("read access is open to authenticated principals")
+	}
+
+	if res.Owner == "" && len(res.Members) == 0 {
+		if res.Service == "" {
+			return deny(fmt.Sprintf("%s requires a platform admin", action))
+		}
+		return deny(fmt.Sprintf("service %s has no registered owner; only platform admins may act on it", res.Service))
+	}
+
+	if res.Owner != "" && owns(p, res.Owner) {
+		return allow(fmt.Sprintf("principal owns service %s", res.Service))
+	}
+
+	for _, m := range res.Members {
+		if p.Subject == m || p.Name == m {
+			return allow(fmt.Sprintf("principal is a member of team %s", res.Team))
+		}
+	}
+
+	if res.Owner == "" {
+		if res.Service == "" {
+			return deny(fmt.Sprintf("principal is not a member of team %s", res.Team))
+		}
+		return deny(fmt.Sprintf("service %s belongs to team %s; principal is not a member", res.Service, res.Team))
+	}
+
+	return deny(fmt.Sprintf("service %s is owned by %s", res.Service, res.Owner))
+}
+
+// IsAdmin reports whether p is a platform admin.
+func (a *Authorizer) IsAdmin(p *auth.Principal) bool {
+	return a.policy.Load().isAdmin(p)
+}
+
+func (pol *policy) isAdmin(p *auth.Principal) bool {
+
+	if pol.adminUsers[p.Subject] || (p.Name != "" && pol.adminUsers[p.Name]) {
+		return true
+	}
+
+	for _, g := range p.Groups {
+		if pol.adminGroups[g] {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// ---- Helpers ----
+//
+
+func owns(p *auth.Principal, owner string) bool {
+
+	group, isGroup := strings.CutPrefix(owner, GroupOwnerPrefix)
+	if !isGroup {
+		return p.ID() == owner || p.Subject == owner || p.Name == owner
+	}
+
+	for _, g := range p.Groups {
+		if g == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRead(action Action) bool {
+	switch action {
+	case ActionReadService, ActionReadEnvironment, ActionReadWorkflows, ActionReadTeam:
+		return true
+	}
+	return false
+}
+
+func allow(reason string) Decision {
+	return Decision{Allowed: true, Reason: reason}
+}
+
+func deny(reason string) Decision {
+	return Decision{Allowed: false, Reason: reason}
+}
+
+func toSet(items []string) map[string]bool {
+	out := make(map[string]bool, len(items))
+	for _, i := range items {
+		out[i] = true
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package authz
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+)
+
+func TestDecide(t *testing.T) {
+
+	alice := &auth.Principal{Subject: "alice", Name: "Alice", Method: auth.MethodOIDC}
+	bob := &auth.Principal{Subject: "bob", Method: auth.MethodOIDC, Groups: []string{"payments"}}
+	root := &auth.Principal{Subject: "root", Method: auth.MethodToken}
+	ops := &auth.Principal{Subject: "carol", Method: auth.MethodOIDC, Groups: []string{"ops"}}
+
+	owned := func(owner string) Resource {
+		return Resource{Kind: "environment", Name: "pr-42", Service: "payments", Owner: owner}
+	}
+	team := Resource{Kind: "team", Name: "payments", Team: "payments", Members: []string{"Alice"}}
+	unowned := Resource{Kind: "environment", Name: "pr-42", Service: "payments"}
+
+	tests := []struct {
+		name      string
+		read      ReadPolicy
+		anonymous bool
+		principal *auth.Principal
+		action    Action
+		res       Resource
+		want      bool
+	}{
+		{"no principal", ReadAuthenticated, false, nil, ActionReadService, owned("alice"), false},
+		{"anonymous without opt-in", ReadAuthenticated, false, auth.Anonymous, ActionReadService, owned("alice"), false},
+		{"anonymous with opt-in", ReadAuthenticated, true, auth.Anonymous, ActionDestroyEnvironment, owned("alice"), true},
+
+		{"admin user", ReadOwner, false, root, ActionDestroyEnvironment, unowned, true},
+		{"admin group", ReadOwner, false, ops, ActionManageTeams, Resource{Kind: "team"}, true},
+
+		{"open reads", ReadAuthenticated, false, bob, ActionReadEnvironment, owned("alice"), true},
+		{"owner reads", ReadOwner, false, bob, ActionReadEnvironment, owned("alice"), false},
+		{"writes need ownership", ReadAuthenticated, false, bob, ActionDestroyEnvironment, owned("alice"), false},
+
+		{"owner by subject", ReadOwner, false, alice, ActionExtendEnvironment, owned("alice"), true},
+		{"owner by id", ReadOwner, false, alice, ActionExtendEnvironment, owned("oidc:alice"), true},
+		{"owner by name", ReadOwner, false, alice, ActionExtendEnvironment, owned("Alice"), true},
+		{"group owner", ReadOwner, false, bob, ActionExtendEnvironment, owned("group:payments"), true},
+		{"group owner is not a user", ReadOwner, false, &auth.Principal{Subject: "payments", Method: auth.MethodOIDC}, ActionExtendEnvironment, owned("group:payments"), false},
+		{"group claim is not a user owner", ReadOwner, false, bob, ActionExtendEnvironment, owned("payments"), false},
+
+		{"team member", ReadOwner, false, alice, ActionReadTeam, team, true},
+		{"not a team member", ReadOwner, false, bob, ActionReadTeam, team, false},
+		{"team reads open", ReadAuthenticated, false, bob, ActionReadTeam, team, true},
+
+		{"no owner", ReadOwner, false, alice, ActionDestroyEnvironment, unowned, false},
+		{"admin only action", ReadAuthenticated, false, alice, ActionReadAudit, Resource{Kind: "audit"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(Config{
+				AdminUsers:     []string{"root"},
+				AdminGroups:    []string{"ops"},
+				Read:           tt.read,
+				AllowAnonymous: tt.anonymous,
+			}, zap.NewNop())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d := a.Decide(tt.principal, tt.action, tt.res)
+			if d.Allowed != tt.want {
+				t.Fatalf("Decide() = %v (%s), want %v", d.Allowed, d.Reason, tt.want)
+			}
+			if d.Reason == "" {
+				t.Fatal("Decide() gave no reason")
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+
+	a, err := New(Config{}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice := &auth.Principal{Subject: "alice", Method: auth.MethodOIDC}
+
+	if a.IsAdmin(alice) {
+		t.Fatal("alice is an admin before the update")
+	}
+
+	if err := a.Update(Config{AdminUsers: []string{"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsAdmin(alice) {
+		t.Fatal("alice is not an admin after the update")
+	}
+
+	if err := a.Update(Config{Read: "everyone"}); err == nil {
+		t.Fatal("Update() accepted an unknown read policy")
+	}
+	if !a.IsAdmin(alice) {
+		t.Fatal("a rejected update replaced the policy")
+	}
+}
@@ -0,0 +1,194 @@
+package blueprint
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Default is the built-in blueprint: a bare namespace created,
+// expired and destroyed by the catalog's env-create, env-ttl and
+// env-destroy templates.
+const Default = "namespace"
+
+var ErrNotFound = errors.New("blueprint not found")
+
+// namePattern matches DNS labels, like the blueprint names accepted
+// in repository manifests.
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// OutputType tells consumers how to treat an environment output.
+// Secret outputs name a Kubernetes Secret; their values are never
+// read by the control plane.
+type OutputType string
+
+const (
+	OutputString OutputType = "string"
+	OutputURL    OutputType = "url"
+	OutputSecret OutputType = "secret"
+)
+
+// Output is a value an environment of a blueprint produces, e.g. its
+// ingress URL.
+type Output struct {
+	Name        string     `json:"name"`
+	Type        OutputType `json:"type"`
+	Description string     `json:"description,omitempty"`
+}
+
+// Blueprint is a named kind of environment.
+//
+// Templates left empty resolve by purpose from the catalog, as for
+// the built-in blueprint. Parameters, when set, restricts the user
+// parameters the create template allows further.
+//
+// A blueprint with Terraform set is Terraform-backed: its templates
+// apply and destroy the module, and user parameters become Terraform
+// variables, allowed only when listed in Parameters.
+type Blueprint struct {
+	Name            string
+	Description     string
+	CreateTemplate  string
+	DestroyTemplate string
+	TTLTemplate     string
+	DefaultTTL      time.Duration
+	Parameters      []string
+	Outputs         []Output
+	Terraform       *Terraform
+}
+
+// Terraform is the module a Terraform-backed blueprint applies.
+//
+// Version pins the module (a tag or commit); environments record the
+// version they were applied with and are destroyed with the same one,
+// whatever the blueprint says by then. State lives under StatePrefix
+// in the backend the templates configure, one key per environment.
+type Terraform struct {
+	Module      string `json:"module"`
+	Version     string `json:"version"`
+	StatePrefix string `json:"state_prefix"`
+}
+
+// Builtin returns the default blueprint.
+func Builtin() Blueprint {
+	return Blueprint{
+		Name:        Default,
+		Description: "Bare namespace",
+		Outputs: []Output{
+			{Name: "namespace", Type: OutputString, Description: "Namespace of the environment"},
+		},
+	}
+}
+
+// AllowsParameter reports whether users may set name on environments
+// of the blueprint. For template-backed blueprints the create template
+// must allow it too.
+func (b Blueprint) AllowsParameter(name string) bool {
+	if b.Terraform != nil {
+		return slices.Contains(b.Parameters, name)
+	}
+	return len(b.Parameters) == 0 || slices.Contains(b.Parameters, name)
+}
+
+// ValidName reports whether name can name a blueprint.
+//
+// Names are DNS labels, so they can appear in labels and manifests
+// unquoted. Compositions are spelled with a hyphen: the blueprint
+// first asked for as "namespace+postgres" is "namespace-postgres".
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// ValidOutputType reports whether t is a known output type.
+func ValidOutputType(t OutputType) bool {
+	switch t {
+	case OutputString, OutputURL, OutputSecret:
+		return true
+	default:
+		return false
+	}
+}
+
+// Registry holds the configured blueprints. They are replaced as a
+// whole on configuration reload, so a request sees one consistent set.
+//
+// The built-in blueprint is always present unless configuration
+// redefines it.
+type Registry struct {
+	current atomic.Pointer[set]
+}
+
+type set struct {
+	blueprints  map[string]Blueprint
+	defaultName string
+}
+
+func NewRegistry(
+	blueprints []Blueprint,
+	defaultName string,
+) *Registry {
+	r := &Registry{}
+	r.Set(blueprints, defaultName)
+	return r
+}
+
+// Set replaces the blueprints. An empty defaultName selects Default.
+func (r *Registry) Set(blueprints []Blueprint, defaultName string) {
+
+	s := &set{
+		blueprints:  map[string]Blueprint{Default: Builtin()},
+		defaultName: defaultName,
+	}
+	if s.defaultName == "" {
+		s.defaultName = Default
+	}
+
+	for _, b := range blueprints {
+		s.blueprints[b.Name] = b
+	}
+
+	r.current.Store(s)
+}
+
+// Get returns a blueprint by name; "" selects the default blueprint.
+func (r *Registry) Get(name string) (Blueprint, error) {
+
+	s := r.current.Load()
+
+	if name == "" {
+		name = s.defaultName
+	}
+
+	b, ok := s.blueprints[name]
+	if !ok {
+		return Blueprint{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	return b, nil
+}
+
+// Default returns the name of the default blueprint.
+func (r *Registry) Default() string {
+	return r.current.Load().defaultName
+}
+
+// List returns every blueprint sorted by name.
+func (r *Registry) List() []Blueprint {
+
+	s := r.current.Load()
+
+	out := make([]Blueprint, 0, len(s.blueprints))
+	for _, b := range s.blueprints {
+		out = append(out, b)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
@@ -0,0 +1,399 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+//
+// Template Label Keys
+//
+// A WorkflowTemplate joins the catalog by carrying LabelCatalog=true.
+// Purpose and language are declared the same way.
+//
+// Adding a new CI language is therefore:
+//
+//	kubectl apply -f go-ci-template.yaml
+//
+// and nothing else.
+//
+
+const (
+	LabelCatalog  = "platform.catalog"
+	LabelPurpose  = "platform.template.purpose"
+	LabelLanguage = "platform.template.language"
+)
+
+// AnnotationUserParameters is the allowlist of declared parameters
+// users may set, each optionally typed (see ParameterType):
+//
+//	platform.template.user-parameters: "image_tag, replicas=int, debug=bool"
+//
+// Every other parameter is set by the platform or left to its default.
+const AnnotationUserParameters = "platform.template.user-parameters"
+
+// Purpose describes what a template is used for.
+type Purpose string
+
+const (
+	PurposeCI         Purpose = "ci"
+	PurposeEnvCreate  Purpose = "env-create"
+	PurposeEnvDestroy Purpose = "env-destroy"
+	PurposeEnvTTL     Purpose = "env-ttl"
+	PurposeDeploy     Purpose = "deploy"
+)
+
+var ErrTemplateNotFound = errors.New("template not found in catalog")
+
+// Parameter is a parameter declared by a template in
+// spec.arguments.parameters.
+//
+// UserSettable and Type come from AnnotationUserParameters.
+type Parameter struct {
+	Name         string        `json:"name"`
+	Default      *string       `json:"default,omitempty"`
+	Enum         []string      `json:"enum,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	Required     bool          `json:"required"`
+	UserSettable bool          `json:"user_settable,omitempty"`
+	Type         ParameterType `json:"type,omitempty"`
+}
+
+// Template is the control-plane view of a discovered WorkflowTemplate.
+type Template struct {
+	Name       string      `json:"name"`
+	Namespace  string      `json:"namespace"`
+	Purpose    Purpose     `json:"purpose"`
+	Language   string      `json:"language,omitempty"`
+	Parameters []Parameter `json:"parameters"`
+}
+
+// Catalog is a read-through cache of platform WorkflowTemplates.
+//
+// It does NOT own templates.
+// The execution plane is the source of truth; Refresh re-reads it.
+//
+// Templates are discovered in the default execution namespace of the
+// default cluster. Routed namespaces and additional clusters are
+// expected to carry the same templates.
+type Catalog struct {
+	source    executor.TemplateReader
+	namespace string
+
+	mu          sync.RWMutex
+	templates   map[string]Template
+	pinned      map[pinKey]string
+	refreshedAt time.Time
+}
+
+// pinKey is what Resolve selects on.
+type pinKey struct {
+	purpose  Purpose
+	language string
+}
+
+func New(
+	source executor.TemplateReader,
+	namespace string,
+) *Catalog {
+	return &Catalog{
+		source:    source,
+		namespace: namespace,
+		templates: make(map[string]Template),
+	}
+}
+
+// Refresh re-discovers labelled templates from the execution plane.
+//
+// If the execution plane is unreachable the previous snapshot is kept.
+// Individually malformed templates are skipped and reported, so one
+// bad label cannot hide every other template.
+func (c *Catalog) Refresh(ctx context.Context) error {
+
+	items, err := c.source.ListTemplates(
+		ctx,
+		executor.Target{Namespace: c.namespace},
+		LabelCatalog+"=true",
+	)
+	if err != nil {
+		return fmt.Errorf("discover templates: %w", err)
+	}
+
+	var errs []error
+
+	templates := make(map[string]Template, len(items))
+	for i := range items {
+		t, err := fromWorkflowTemplate(&items[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		templates[t.Name] = t
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.templates = templates
+	c.refreshedAt = time.Now().UTC()
+
+	return errors.Join(errs...)
+}
+
+// Run refreshes the catalog every interval until ctx is cancelled.
+func (c *Catalog) Run(
+	ctx context.Context,
+	interval time.Duration,
+	onError func(error),
+) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// List returns every known template sorted by name.
+func (c *Catalog) List() []Template {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]Template, 0, len(c.templates))
+	for _, t := range c.templates {
+		out = append(out, t)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
+
+// Get returns a template by name.
+func (c *Catalog) Get(name string) (Template, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, ok := c.templates[name]
+	if !ok {
+		return Template{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	return t, nil
+}
+
+// Resolve selects the template for a purpose and language.
+//
+// Environment purposes are language-agnostic; pass "".
+// A pinned template wins; otherwise, when several templates match,
+// the lowest name wins so that resolution is deterministic across
+// replicas.
+func (c *Catalog) Resolve(
+	purpose Purpose,
+	language string,
+) (Template, error) {
+
+	c.mu.RLock()
+	name, pinned := c.pinned[pinKey{purpose: purpose, language: language}]
+	c.mu.RUnlock()
+
+	if pinned {
+		t, err := c.Get(name)
+		if err != nil {
+			return Template{}, fmt.Errorf("pinned for %s: %w", pinKeyString(purpose, language), err)
+		}
+		if t.Purpose != purpose || t.Language != language {
+			return Template{}, fmt.Errorf(
+				"%w: pinned template %s is not labelled for %s",
+				ErrTemplateNotFound,
+				name,
+				pinKeyString(purpose, language),
+			)
+		}
+		return t, nil
+	}
+
+	for _, t := range c.List() {
+		if t.Purpose == purpose && t.Language == language {
+			return t, nil
+		}
+	}
+
+	if language != "" {
+		return Template{}, fmt.Errorf(
+			"%w: purpose=%s language=%s",
+			ErrTemplateNotFound,
+			purpose,
+			language,
+		)
+	}
+
+	return Template{}, fmt.Errorf("%w: purpose=%s", ErrTemplateNotFound, purpose)
+}
+
+// Pin replaces the pinned templates, keyed "<purpose>" or
+// "ci/<language>" (see ParsePinKey). It is safe to call while serving.
+func (c *Catalog) Pin(pins map[string]string) error {
+
+	pinned := make(map[pinKey]string, len(pins))
+
+	for key, name := range pins {
+		purpose, language, err := ParsePinKey(key)
+		if err != nil {
+			return err
+		}
+		pinned[pinKey{purpose: purpose, language: language}] = name
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pinned = pinned
+
+	return nil
+}
+
+// ValidatePins reports whether Pin would accept pins.
+func ValidatePins(pins map[string]string) error {
+
+	for key := range pins {
+		if _, _, err := ParsePinKey(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParsePinKey parses "<purpose>" or "ci/<language>".
+func ParsePinKey(key string) (Purpose, string, error) {
+
+	raw, language, hasLanguage := strings.Cut(key, "/")
+	purpose := Purpose(raw)
+
+	switch purpose {
+	case PurposeCI:
+		if !hasLanguage || language == "" {
+			return "", "", fmt.Errorf("ci templates are pinned per language, e.g. ci/go")
+		}
+	case PurposeEnvCreate, PurposeEnvDestroy, PurposeEnvTTL, PurposeDeploy:
+		if hasLanguage {
+			return "", "", fmt.Errorf("%s templates are language-agnostic", purpose)
+		}
+	default:
+		return "", "", fmt.Errorf(
+			"unknown purpose %q (want %s/<language>, %s, %s, %s or %s)",
+			raw,
+			PurposeCI,
+			PurposeEnvCreate,
+			PurposeEnvDestroy,
+			PurposeEnvTTL,
+			PurposeDeploy,
+		)
+	}
+
+	return purpose, language, nil
+}
+
+// RefreshedAt reports when the catalog was last successfully refreshed.
+func (c *Catalog) RefreshedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.refreshedAt
+}
+
+//
+// ---- Helpers ----
+//
+
+func pinKeyString(purpose Purpose, language string) string {
+	if language == "" {
+		return string(purpose)
+	}
+	return string(purpose) + "/" + language
+}
+
+func fromWorkflowTemplate(w *wf.WorkflowTemplate) (Template, error) {
+
+	purpose := Purpose(w.Labels[LabelPurpose])
+
+	switch purpose {
+	case PurposeCI, PurposeEnvCreate, PurposeEnvDestroy, PurposeEnvTTL, PurposeDeploy:
+	default:
+		return Template{}, fmt.Errorf(
+			"template %s: unknown %s %q",
+			w.Name,
+			LabelPurpose,
+			purpose,
+		)
+	}
+
+	params := ParametersOf(w)
+
+	if err := allowUserParameters(params, w.Annotations[AnnotationUserParameters]); err != nil {
+		return Template{}, fmt.Errorf("template %s: %s: %w", w.Name, AnnotationUserParameters, err)
+	}
+
+	return Template{
+		Name:       w.Name,
+		Namespace:  w.Namespace,
+		Purpose:    purpose,
+		Language:   w.Labels[LabelLanguage],
+		Parameters: params,
+	}, nil
+}
+
+// ParametersOf returns the parameters a WorkflowTemplate declares.
+// A parameter without a value or default is required.
+func ParametersOf(w *wf.WorkflowTemplate) []Parameter {
+
+	params := make([]Parameter, 0, len(w.Spec.Arguments.Parameters))
+
+	for _, p := range w.Spec.Arguments.Parameters {
+		param := Parameter{
+			Name:     p.Name,
+			Required: p.Value == nil && p.Default == nil,
+		}
+
+		if p.Description != nil {
+			param.Description = p.Description.String()
+		}
+
+		switch {
+		case p.Value != nil:
+			v := p.Value.String()
+			param.Default = &v
+		case p.Default != nil:
+			v := p.Default.String()
+			param.Default = &v
+		}
+
+		for _, e := range p.Enum {
+			param.Enum = append(param.Enum, e.String())
+		}
+
+		params = append(params, param)
+	}
+
+	return params
+}
@@ -0,0 +1,204 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+func TestRefresh(t *testing.T) {
+
+	source := &fakeSource{templates: []wf.WorkflowTemplate{
+		template("node-ci", PurposeCI, "node"),
+		template("env-create", PurposeEnvCreate, ""),
+		template("mystery", "build", ""),
+	}}
+
+	c := New(source, "argo")
+
+	err := c.Refresh(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "mystery") {
+		t.Fatalf("Refresh() = %v, want the malformed template reported", err)
+	}
+	if got := names(c.List()); got != "env-create node-ci" {
+		t.Fatalf("List() = %q, want the well-formed templates", got)
+	}
+	if c.RefreshedAt().IsZero() {
+		t.Fatal("RefreshedAt() not set")
+	}
+
+	source.err = errors.New("connection refused")
+
+	if err := c.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() succeeded with the execution plane down")
+	}
+	if got := names(c.List()); got != "env-create node-ci" {
+		t.Fatalf("List() = %q, want the previous snapshot kept", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+
+	c := newTestCatalog(t,
+		template("node-ci-v2", PurposeCI, "node"),
+		template("node-ci", PurposeCI, "node"),
+		template("go-ci", PurposeCI, "go"),
+		template("env-create", PurposeEnvCreate, ""),
+		template("env-create-v2", PurposeEnvCreate, ""),
+	)
+
+	tests := []struct {
+		name     string
+		pins     map[string]string
+		purpose  Purpose
+		language string
+		want     string
+		wantErr  bool
+	}{
+		{name: "lowest name wins", purpose: PurposeCI, language: "node", want: "node-ci"},
+		{name: "by language", purpose: PurposeCI, language: "go", want: "go-ci"},
+		{name: "language-agnostic", purpose: PurposeEnvCreate, want: "env-create"},
+		{name: "unknown language", purpose: PurposeCI, language: "rust", wantErr: true},
+		{name: "no template for purpose", purpose: PurposeDeploy, wantErr: true},
+		{
+			name:     "ci pin",
+			pins:     map[string]string{"ci/node": "node-ci-v2"},
+			purpose:  PurposeCI,
+			language: "node",
+			want:     "node-ci-v2",
+		},
+		{
+			name:    "purpose pin",
+			pins:    map[string]string{"env-create": "env-create-v2"},
+			purpose: PurposeEnvCreate,
+			want:    "env-create-v2",
+		},
+		{
+			name:     "pin of another language",
+			pins:     map[string]string{"ci/node": "go-ci"},
+			purpose:  PurposeCI,
+			language: "node",
+			wantErr:  true,
+		},
+		{
+			name:    "pin of a missing template",
+			pins:    map[string]string{"env-create": "env-create-v3"},
+			purpose: PurposeEnvCreate,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.Pin(tt.pins); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := c.Resolve(tt.purpose, tt.language)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrTemplateNotFound) {
+					t.Fatalf("Resolve() = %v, want ErrTemplateNotFound", err)
+				}
+				return
+			}
+
+			if err != nil || got.Name != tt.want {
+				t.Fatalf("Resolve() = %q, %v, want %q", got.Name, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePinKey(t *testing.T) {
+
+	tests := []struct {
+		key          string
+		wantPurpose  Purpose
+		wantLanguage string
+		wantErr      bool
+	}{
+		{key: "ci/go", wantPurpose: PurposeCI, wantLanguage: "go"},
+		{key: "env-create", wantPurpose: PurposeEnvCreate},
+		{key: "deploy", wantPurpose: PurposeDeploy},
+		{key: "ci", wantErr: true},
+		{key: "ci/", wantErr: true},
+		{key: "env-ttl/go", wantErr: true},
+		{key: "build", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			purpose, language, err := ParsePinKey(tt.key)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParsePinKey() = %s, %s, want an error", purpose, language)
+				}
+				return
+			}
+
+			if err != nil || purpose != tt.wantPurpose || language != tt.wantLanguage {
+				t.Fatalf("ParsePinKey() = %s, %s, %v", purpose, language, err)
+			}
+		})
+	}
+
+	if err := ValidatePins(map[string]string{"ci/go": "go-ci", "build": "x"}); err == nil {
+		t.Fatal("ValidatePins() accepted an unknown purpose")
+	}
+}
+
+// ---- Helpers ----
+
+type fakeSource struct {
+	executor.TemplateReader
+
+	templates []wf.WorkflowTemplate
+	err       error
+}
+
+func (s *fakeSource) ListTemplates(context.Context, executor.Target, string) ([]wf.WorkflowTemplate, error) {
+	return s.templates, s.err
+}
+
+func newTestCatalog(t *testing.T, templates ...wf.WorkflowTemplate) *Catalog {
+
+	t.Helper()
+
+	c := New(&fakeSource{templates: templates}, "argo")
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func template(name string, purpose Purpose, language string) wf.WorkflowTemplate {
+
+	labels := map[string]string{
+		LabelCatalog: "true",
+		LabelPurpose: string(purpose),
+	}
+	if language != "" {
+		labels[LabelLanguage] = language
+	}
+
+	return wf.WorkflowTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argo", Labels: labels},
+	}
+}
+
+func names(templates []Template) string {
+	out := make([]string, len(templates))
+	for i, t := range templates {
+		out[i] = t.Name
+	}
+	return strings.Join(out, " ")
+}
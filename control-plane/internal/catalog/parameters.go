@@ -0,0 +1,132 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParameterType is the type of a user-settable parameter. Argo passes
+// every parameter as a string; the type only constrains its format.
+type ParameterType string
+
+const (
+	ParameterString ParameterType = "string"
+	ParameterInt    ParameterType = "int"
+	ParameterBool   ParameterType = "bool"
+)
+
+// ErrInvalidParameters is returned when user-supplied parameters are
+// not allowed by a template or do not match its declared types.
+var ErrInvalidParameters = errors.New("invalid parameters")
+
+// ValidateUserParameters checks user-supplied parameters against the
+// template's allowlist, types and enums. All problems are reported
+// at once.
+func (t Template) ValidateUserParameters(params map[string]string) error {
+
+	declared := make(map[string]Parameter, len(t.Parameters))
+	for _, p := range t.Parameters {
+		declared[p.Name] = p
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+
+	for _, name := range names {
+		p, ok := declared[name]
+		if !ok || !p.UserSettable {
+			problems = append(problems, fmt.Sprintf("%s: not settable on template %s", name, t.Name))
+			continue
+		}
+
+		if err := p.Type.check(params[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, params[name]) {
+			problems = append(problems, fmt.Sprintf("%s: want one of %s", name, strings.Join(p.Enum, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidParameters, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+//
+// ---- Helpers ----
+//
+
+func (t ParameterType) check(value string) error {
+
+	switch t {
+	case ParameterString:
+	case ParameterInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("want an integer, got %q", value)
+		}
+	case ParameterBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("want true or false, got %q", value)
+		}
+	}
+
+	return nil
+}
+
+// allowUserParameters marks the parameters listed in an
+// AnnotationUserParameters value as user-settable.
+func allowUserParameters(params []Parameter, annotation string) error {
+
+	if strings.TrimSpace(annotation) == "" {
+		return nil
+	}
+
+	for _, entry := range strings.Split(annotation, ",") {
+
+		name, typ, typed := strings.Cut(strings.TrimSpace(entry), "=")
+		if name == "" {
+			return fmt.Errorf("empty parameter name in %q", annotation)
+		}
+
+		ptype := ParameterString
+		if typed {
+			ptype = ParameterType(typ)
+		}
+
+		switch ptype {
+		case ParameterString, ParameterInt, ParameterBool:
+		default:
+			return fmt.Errorf(
+				"parameter %s: unknown type %q (want %s, %s or %s)",
+				name,
+				typ,
+				ParameterString,
+				ParameterInt,
+				ParameterBool,
+			)
+		}
+
+		i := slices.IndexFunc(params, func(p Parameter) bool { return p.Name == name })
+		if i < 0 {
+			return fmt.Errorf("parameter %s is not declared in spec.arguments.parameters", name)
+		}
+
+		params[i].UserSettable = true
+		params[i].Type = ptype
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+)
+
+func TestUserParametersAnnotation(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		annotation string
+		want       map[string]ParameterType
+		wantErr    string
+	}{
+		{
+			name: "none",
+			want: map[string]ParameterType{},
+		},
+		{
+			name:       "typed and untyped",
+			annotation: " image_tag, replicas=int ,debug=bool",
+			want:       map[string]ParameterType{"image_tag": ParameterString, "replicas": ParameterInt, "debug": ParameterBool},
+		},
+		{
+			name:       "unknown type",
+			annotation: "replicas=float",
+			wantErr:    `parameter replicas: unknown type "float"`,
+		},
+		{
+			name:       "undeclared parameter",
+			annotation: "image_tag, region",
+			wantErr:    "parameter region is not declared",
+		},
+		{
+			name:       "empty entry",
+			annotation: "image_tag,,debug",
+			wantErr:    "empty parameter name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := template("env-create", PurposeEnvCreate, "")
+			w.Annotations = map[string]string{AnnotationUserParameters: tt.annotation}
+			w.Spec.Arguments.Parameters = []wf.Parameter{
+				{Name: "env_name"},
+				{Name: "image_tag", Default: wf.AnyStringPtr("latest")},
+				{Name: "replicas", Default: wf.AnyStringPtr("1")},
+				{Name: "debug", Default: wf.AnyStringPtr("false")},
+			}
+
+			got, err := fromWorkflowTemplate(&w)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("fromWorkflowTemplate() = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, p := range got.Parameters {
+				typ, settable := tt.want[p.Name]
+				if p.UserSettable != settable || p.Type != typ {
+					t.Fatalf("%s: settable %v type %q, want %v %q", p.Name, p.UserSettable, p.Type, settable, typ)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateUserParameters(t *testing.T) {
+
+	tmpl := Template{
+		Name: "env-create",
+		Parameters: []Parameter{
+			{Name: "env_name", Required: true},
+			{Name: "image_tag", UserSettable: true, Type: ParameterString},
+			{Name: "replicas", UserSettable: true, Type: ParameterInt},
+			{Name: "debug", UserSettable: true, Type: ParameterBool},
+			{Name: "size", UserSettable: true, Type: ParameterString, Enum: []string{"small", "large"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		wantErr []string
+	}{
+		{
+			name:   "allowed",
+			params: map[string]string{"image_tag": "v1", "replicas": "3", "debug": "true", "size": "large"},
+		},
+		{
+			name:   "none",
+			params: nil,
+		},
+		{
+			name:   "every problem at once",
+			params: map[string]string{"env_name": "other", "region": "eu", "replicas": "three", "debug": "yes", "size": "huge"},
+			wantErr: []string{
+				"env_name: not settable on template env-create",
+				"region: not settable on template env-create",
+				`replicas: want an integer, got "three"`,
+				`debug: want true or false, got "yes"`,
+				"size: want one of small, large",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tmpl.ValidateUserParameters(tt.params)
+
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateUserParameters() = %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidParameters) {
+				t.Fatalf("ValidateUserParameters() = %v, want ErrInvalidParameters", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("ValidateUserParameters() = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
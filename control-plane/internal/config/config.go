@@ -1,24 +1,292 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
+// Config is the control plane configuration.
+//
+// It is assembled in layers, later layers winning:
+//
+//	defaults       Default()
+//	config file    YAML, keys as in the json tags below
+//	environment    the variables read in load.go
+//
+// and validated as a whole. See config.example.yaml.
 type Config struct {
-	ServiceName string
-	Environment string
+	ServiceName string `json:"service_name"`
+	Environment string `json:"environment"`
 
-	HTTP HTTPConfig
-	Log  LogConfig
+	HTTP         HTTPConfig         `json:"http"`
+	Log          LogConfig          `json:"log"`
+	Executor     ExecutorConfig     `json:"executor"`
+	Templates    TemplatesConfig    `json:"templates"`
+	Environments EnvironmentsConfig `json:"environments"`
+	Blueprints   BlueprintsConfig   `json:"blueprints,omitempty"`
+	Quotas       QuotasConfig       `json:"quotas"`
+	Providers    ProvidersConfig    `json:"providers"`
+	Auth         AuthConfig         `json:"auth"`
+	RateLimit    RateLimitConfig    `json:"rate_limit"`
+	Audit        AuditConfig        `json:"audit"`
+	Tracing      TracingConfig      `json:"tracing"`
 }
 
+// HTTPConfig configures the API listener. With TLS set, the API is
+// served over HTTPS only.
 type HTTPConfig struct {
-	Address         string
-	ReadTimeout     time.Duration
-	WriteTimeout    time.Duration
-	ShutdownTimeout time.Duration
+	Address         string    `json:"address"`
+	ReadTimeout     Duration  `json:"read_timeout"`
+	WriteTimeout    Duration  `json:"write_timeout"`
+	IdleTimeout     Duration  `json:"idle_timeout"`
+	ShutdownTimeout Duration  `json:"shutdown_timeout"`
+	TLS             TLSConfig `json:"tls"`
+}
+
+// TLSConfig names a PEM certificate and key, typically a mounted
+// kubernetes.io/tls Secret. Both files are re-read on reload, so
+// rotated certificates are picked up without a restart.
+type TLSConfig struct {
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+}
+
+// Enabled reports whether TLS is configured.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
 }
 
 type LogConfig struct {
-	Level string
+	Level string `json:"level"`
+}
+
+// Execution backends.
+const (
+	BackendArgo = "argo"
+	BackendJobs = "jobs"
+	BackendFake = "fake"
+)
+
+// ExecutorConfig controls where workflows are executed.
+//
+// ServiceNamespaces and TeamNamespaces route workflows away from
+// Namespace; a service route wins over a team route.
+//
+// DefaultCluster names the cluster the control plane runs in.
+// Clusters and ClusterConfigDir add further named clusters, and
+// Placement spreads environments across them.
+//
+// Backend selects the execution plane: "argo" (default), "jobs" for
+// clusters that cannot run Argo, or "fake" for an in-memory simulation
+// driven by SimulationScript and seeded from SimulationTemplateDirs.
+type ExecutorConfig struct {
+	Backend string `json:"backend"`
+
+	Namespace         string            `json:"namespace"`
+	ServiceNamespaces map[string]string `json:"service_namespaces,omitempty"`
+	TeamNamespaces    map[string]string `json:"team_namespaces,omitempty"`
+
+	DefaultCluster   string            `json:"default_cluster"`
+	Clusters         []ClusterConfig   `json:"clusters,omitempty"`
+	ClusterConfigDir string            `json:"cluster_config_dir,omitempty"`
+	Placement        []PlacementConfig `json:"placement,omitempty"`
+
+	SimulationScript       string   `json:"simulation_script,omitempty"`
+	SimulationTemplateDirs []string `json:"simulation_template_dirs,omitempty"`
+}
+
+// ClusterConfig names a kubeconfig context to connect to.
+type ClusterConfig struct {
+	Name    string `json:"name"`
+	Context string `json:"context"`
+}
+
+// PlacementConfig places environments of a service (glob) or team
+// on a cluster.
+type PlacementConfig struct {
+	Service string `json:"service,omitempty"`
+	Team    string `json:"team,omitempty"`
+	Cluster string `json:"cluster"`
+}
+
+// TemplatesConfig controls the template catalog.
+//
+// Templates are discovered in Namespace (default: the executor
+// namespace) every RefreshInterval. Pinned selects a template by name
+// for a purpose, keyed "<purpose>" or "ci/<language>", instead of the
+// lowest-named match, e.g. to roll out env-create-v2 explicitly.
+//
+// At startup every resolved template is checked in every execution
+// namespace against the parameters the platform submits. Problems are
+// logged; StrictPreflight refuses to start on missing required ones.
+type TemplatesConfig struct {
+	Namespace       string            `json:"namespace,omitempty"`
+	RefreshInterval Duration          `json:"refresh_interval"`
+	Pinned          map[string]string `json:"pinned,omitempty"`
+	StrictPreflight bool              `json:"strict_preflight"`
+}
+
+// EnvironmentsConfig is the platform-wide environment policy.
+//
+// DefaultTTL applies when neither the request, the repository manifest
+// nor the blueprint sets one; without it a TTL is required. MaxTTL
+// caps every environment, registered service or not; team quotas may
+// be lower. Zero disables either.
+//
+// DefaultBlueprint applies when neither the request, the manifest nor
+// the service registration selects one.
+type EnvironmentsConfig struct {
+	DefaultTTL       Duration `json:"default_ttl"`
+	MaxTTL           Duration `json:"max_ttl"`
+	DefaultBlueprint string   `json:"default_blueprint"`
+}
+
+// BlueprintsConfig maps blueprint names to their definitions. The
+// built-in "namespace" blueprint exists unless redefined here.
+type BlueprintsConfig map[string]BlueprintConfig
+
+// BlueprintConfig mirrors blueprint.Blueprint. Templates left empty
+// resolve by purpose from the catalog, except for Terraform-backed
+// blueprints, which name all three.
+type BlueprintConfig struct {
+	Description     string           `json:"description,omitempty"`
+	CreateTemplate  string           `json:"create_template,omitempty"`
+	DestroyTemplate string           `json:"destroy_template,omitempty"`
+	TTLTemplate     string           `json:"ttl_template,omitempty"`
+	DefaultTTL      Duration         `json:"default_ttl"`
+	Parameters      []string         `json:"parameters,omitempty"`
+	Outputs         []OutputConfig   `json:"outputs,omitempty"`
+	Terraform       *TerraformConfig `json:"terraform,omitempty"`
+}
+
+// TerraformConfig mirrors blueprint.Terraform. StatePrefix defaults to
+// "environments".
+type TerraformConfig struct {
+	Module      string `json:"module"`
+	Version     string `json:"version"`
+	StatePrefix string `json:"state_prefix,omitempty"`
+}
+
+type OutputConfig struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// QuotasConfig holds the quotas of teams that leave a quota unset.
+type QuotasConfig struct {
+	Defaults QuotaLimits `json:"defaults"`
+}
+
+// QuotaLimits mirrors quota.Limits. Zero means unlimited.
+type QuotaLimits struct {
+	MaxEnvironments             int      `json:"max_environments"`
+	MaxTTL                      Duration `json:"max_ttl"`
+	MaxConcurrentRuns           int      `json:"max_concurrent_runs"`
+	MaxEnvironmentHoursPerMonth float64  `json:"max_environment_hours_per_month"`
+}
+
+type ProvidersConfig struct {
+	GitHubToken string `json:"github_token,omitempty"`
+}
+
+// AuthConfig controls API authentication and authorization.
+//
+// At least one of TokensFile or OIDC.Issuer must be set unless
+// Disabled, which is meant for local development only.
+//
+// AdminUsers and AdminGroups may act on every service. ReadPolicy is
+// "authenticated" (anyone signed in may read) or "owner".
+type AuthConfig struct {
+	Disabled   bool       `json:"disabled"`
+	TokensFile string     `json:"tokens_file,omitempty"`
+	OIDC       OIDCConfig `json:"oidc"`
+
+	AdminUsers  []string `json:"admin_users,omitempty"`
+	AdminGroups []string `json:"admin_groups,omitempty"`
+	ReadPolicy  string   `json:"read_policy"`
+}
+
+// OIDCConfig validates JWTs from one issuer. Keys are read from
+// JWKSFile, else fetched from JWKSURL, else discovered from Issuer.
+type OIDCConfig struct {
+	Issuer        string `json:"issuer,omitempty"`
+	Audience      string `json:"audience,omitempty"`
+	JWKSURL       string `json:"jwks_url,omitempty"`
+	JWKSFile      string `json:"jwks_file,omitempty"`
+	UsernameClaim string `json:"username_claim"`
+	GroupsClaim   string `json:"groups_claim"`
+}
+
+// RateLimitConfig protects the API and the execution plane.
+//
+// Client is a token bucket per client IP, charged before
+// authentication so that unauthenticated floods and credential
+// guessing are bounded too. Read and Submit are token buckets per
+// principal (or client IP): Submit covers endpoints that submit
+// workflows, Read everything else. Rates are requests per second; a
+// zero rate disables that budget.
+//
+// MaxInFlightPerService caps concurrent submission requests per
+// service, i.e. submit calls in progress, not running workflows; zero
+// disables the cap.
+type RateLimitConfig struct {
+	ClientRate  float64 `json:"client_rps"`
+	ClientBurst int     `json:"client_burst"`
+	ReadRate    float64 `json:"read_rps"`
+	ReadBurst   int     `json:"read_burst"`
+	SubmitRate  float64 `json:"submit_rps"`
+	SubmitBurst int     `json:"submit_burst"`
+
+	MaxInFlightPerService int `json:"max_inflight_per_service"`
+}
+
+// AuditConfig selects where audit records go. With File set, records
+// are appended to it as JSON lines; otherwise the most recent records
+// are kept in memory only, lost on restart and not shared between
+// replicas.
+type AuditConfig struct {
+	File string `json:"file,omitempty"`
+}
+
+// TracingConfig selects the OpenTelemetry span exporter: "none"
+// (default), "otlp" (configured by the standard OTEL_EXPORTER_OTLP_*
+// variables), "stdout", or "file" writing JSON spans to File.
+// SampleRatio applies to traces started by the control plane; incoming
+// sampled traces are always continued.
+type TracingConfig struct {
+	Exporter    string  `json:"exporter"`
+	File        string  `json:"file,omitempty"`
+	SampleRatio float64 `json:"sample_ratio"`
+}
+
+// Duration is a time.Duration written as "90s", "1h30m" etc.
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"90s\" or \"1h\"")
+	}
+
+	if s == "" {
+		d.Duration = 0
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	d.Duration = parsed
+	return nil
 }
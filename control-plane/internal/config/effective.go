@@ -0,0 +1,81 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// redacted replaces secret values in printed configuration.
+const redacted = "REDACTED"
+
+// Redacted returns a copy of c with secrets replaced, safe to log or
+// print. Unset secrets stay empty, so "not configured" is visible.
+func (c *Config) Redacted() *Config {
+
+	out := *c
+
+	if out.Providers.GitHubToken != "" {
+		out.Providers.GitHubToken = redacted
+	}
+
+	return &out
+}
+
+// YAML renders the redacted configuration in config file format.
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c.Redacted())
+}
+
+// WithReloadable returns a copy of c with the settings a running
+// control plane applies on reload taken from next: log level, template
+// pins, environment policy, blueprints, default quotas and the admin
+// and read policy.
+func (c *Config) WithReloadable(next *Config) *Config {
+
+	out := *c
+
+	out.Log.Level = next.Log.Level
+	out.Templates.Pinned = next.Templates.Pinned
+	out.Environments = next.Environments
+	out.Blueprints = next.Blueprints
+	out.Quotas = next.Quotas
+	out.Auth.AdminUsers = next.Auth.AdminUsers
+	out.Auth.AdminGroups = next.Auth.AdminGroups
+	out.Auth.ReadPolicy = next.Auth.ReadPolicy
+
+	return &out
+}
+
+// RestartRequired lists the keys that differ between the running
+// configuration and next but are not reloadable, such as
+// "http.address".
+func RestartRequired(running, next *Config) []string {
+	return diff("", reflect.ValueOf(*running.WithReloadable(next)), reflect.ValueOf(*next))
+}
+
+//
+// ---- Helpers ----
+//
+
+// diff walks nested config structs and reports differing leaves by
+// their json key. Durations, slices and maps are leaves.
+func diff(prefix string, a, b reflect.Value) []string {
+
+	if a.Kind() != reflect.Struct || a.Type() == reflect.TypeOf(Duration{}) {
+		if reflect.DeepEqual(a.Interface(), b.Interface()) {
+			return nil
+		}
+		return []string{prefix}
+	}
+
+	var out []string
+
+	for i := 0; i < a.NumField(); i++ {
+		name, _, _ := strings.Cut(a.Type().Field(i).Tag.Get("json"), ",")
+		out = append(out, diff(joinKey(prefix, name), a.Field(i), b.Field(i))...)
+	}
+
+	return out
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithReloadable(t *testing.T) {
+
+	running := Default()
+	running.Providers.GitHubToken = "ghp_running"
+
+	next := Default()
+	next.Log.Level = "debug"
+	next.Templates.Pinned = map[string]string{"ci/go": "go-ci"}
+	next.Environments.MaxTTL = Duration{24 * time.Hour}
+	next.Auth.AdminUsers = []string{"alice"}
+	next.HTTP.Address = ":9090"
+	next.Executor.Namespace = "ci"
+
+	got := running.WithReloadable(next)
+
+	if got.Log.Level != "debug" || got.Templates.Pinned["ci/go"] != "go-ci" ||
+		got.Environments.MaxTTL.Duration != 24*time.Hour || len(got.Auth.AdminUsers) != 1 {
+		t.Fatalf("reloadable settings not applied: %+v", got)
+	}
+	if got.HTTP.Address != ":8080" || got.Executor.Namespace != "argo" || got.Providers.GitHubToken != "ghp_running" {
+		t.Fatalf("restart-only settings applied: %+v", got)
+	}
+
+	want := []string{"http.address", "executor.namespace", "providers.github_token"}
+	if changed := RestartRequired(running, next); !reflect.DeepEqual(changed, want) {
+		t.Fatalf("RestartRequired() = %v, want %v", changed, want)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+
+	c := Default()
+	c.Providers.GitHubToken = "ghp_secret"
+
+	if got := c.Redacted().Providers.GitHubToken; got != redacted {
+		t.Fatalf("Redacted() token = %q", got)
+	}
+	if c.Providers.GitHubToken != "ghp_secret" {
+		t.Fatal("Redacted() changed the original")
+	}
+	if got := Default().Redacted().Providers.GitHubToken; got != "" {
+		t.Fatalf("unset token redacted to %q", got)
+	}
+}
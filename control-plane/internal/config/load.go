@@ -1,24 +1,341 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
 )
 
-func Load() *Config {
+// Load builds the configuration from defaults, the YAML file at path
+// (optional) and environment variables, then validates it.
+//
+// It is called again on reload, so it must not have side effects.
+func Load(path string) (*Config, error) {
+
+	cfg := Default()
+
+	if path != "" {
+		if err := readFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Default is the configuration of a control plane started without a
+// config file or environment.
+func Default() *Config {
 	return &Config{
 		ServiceName: "self-service-cicd-control-plane",
-		Environment: getEnv("ENVIRONMENT", "local"),
+		Environment: "local",
 		HTTP: HTTPConfig{
-			Address:         getEnv("HTTP_ADDRESS", ":8080"),
-			ReadTimeout:     5 * time.Second,
-			WriteTimeout:    10 * time.Second,
-			ShutdownTimeout: 15 * time.Second,
+			Address:         ":8080",
+			ReadTimeout:     Duration{5 * time.Second},
+			WriteTimeout:    Duration{10 * time.Second},
+			IdleTimeout:     Duration{time.Minute},
+			ShutdownTimeout: Duration{15 * time.Second},
 		},
 		Log: LogConfig{
-			Level: getEnv("LOG_LEVEL", "info"),
+			Level: "info",
+		},
+		Executor: ExecutorConfig{
+			Backend:        BackendArgo,
+			Namespace:      "argo",
+			DefaultCluster: "local",
+			SimulationTemplateDirs: []string{
+				"../argo/workflowtemplates",
+				"../workflows/templates",
+			},
+		},
+		Templates: TemplatesConfig{
+			RefreshInterval: Duration{time.Minute},
+		},
+		Environments: EnvironmentsConfig{
+			DefaultBlueprint: blueprint.Default,
 		},
+		Auth: AuthConfig{
+			OIDC: OIDCConfig{
+				UsernameClaim: "email",
+				GroupsClaim:   "groups",
+			},
+			AdminGroups: []string{"platform-admins"},
+			ReadPolicy:  "authenticated",
+		},
+		RateLimit: RateLimitConfig{
+			ClientRate:            50,
+			ClientBurst:           100,
+			ReadRate:              10,
+			ReadBurst:             20,
+			SubmitRate:            0.2,
+			SubmitBurst:           5,
+			MaxInFlightPerService: 3,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// readFile overlays the YAML file at path onto cfg. Unknown keys are
+// errors, so that a typo cannot silently leave a default in place.
+func readFile(path string, cfg *Config) error {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	// Report unknown keys and bad durations by their full key; the
+	// decoder below only names the innermost field.
+	v := &validator{}
+	checkKeys(v, "", raw, reflect.TypeOf(Config{}))
+	if err := v.err(); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// checkKeys walks a decoded YAML document alongside the config type.
+func checkKeys(v *validator, key string, value interface{}, t reflect.Type) {
+
+	if value == nil {
+		return
+	}
+
+	switch {
+	case t == reflect.TypeOf(Duration{}):
+		s, ok := value.(string)
+		if !ok {
+			v.add(key, "must be a duration such as \"90s\" or \"1h\"")
+			return
+		}
+		if _, err := time.ParseDuration(s); s != "" && err != nil {
+			v.add(key, "invalid duration %q, want e.g. \"90s\" or \"1h\"", s)
+		}
+
+	case t.Kind() == reflect.Pointer:
+		checkKeys(v, key, value, t.Elem())
+
+	case t.Kind() == reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			v.add(key, "must be a mapping")
+			return
+		}
+
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			fields[name] = t.Field(i).Type
+		}
+
+		for _, k := range sortedKeys(m) {
+			ft, known := fields[k]
+			if !known {
+				v.add(joinKey(key, k), "unknown key")
+				continue
+			}
+			checkKeys(v, joinKey(key, k), m[k], ft)
+		}
+
+	case t.Kind() == reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			v.add(key, "must be a list")
+			return
+		}
+		for i, item := range items {
+			checkKeys(v, fmt.Sprintf("%s[%d]", key, i), item, t.Elem())
+		}
+
+	case t.Kind() == reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			v.add(key, "must be a mapping")
+			return
+		}
+		for _, k := range sortedKeys(m) {
+			checkKeys(v, joinKey(key, k), m[k], t.Elem())
+		}
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// applyEnv overlays environment variables onto cfg. Unset (or empty)
+// variables leave the file or default value in place.
+func applyEnv(cfg *Config) error {
+
+	var err error
+
+	cfg.Environment = getEnv("ENVIRONMENT", cfg.Environment)
+
+	//-----------------------------------------
+	// HTTP
+	//-----------------------------------------
+
+	cfg.HTTP.Address = getEnv("HTTP_ADDRESS", cfg.HTTP.Address)
+
+	if cfg.HTTP.ReadTimeout, err = getDuration("HTTP_READ_TIMEOUT", cfg.HTTP.ReadTimeout); err != nil {
+		return err
+	}
+	if cfg.HTTP.WriteTimeout, err = getDuration("HTTP_WRITE_TIMEOUT", cfg.HTTP.WriteTimeout); err != nil {
+		return err
+	}
+	if cfg.HTTP.ShutdownTimeout, err = getDuration("HTTP_SHUTDOWN_TIMEOUT", cfg.HTTP.ShutdownTimeout); err != nil {
+		return err
+	}
+
+	cfg.HTTP.TLS.CertFile = getEnv("TLS_CERT_FILE", cfg.HTTP.TLS.CertFile)
+	cfg.HTTP.TLS.KeyFile = getEnv("TLS_KEY_FILE", cfg.HTTP.TLS.KeyFile)
+
+	cfg.Log.Level = getEnv("LOG_LEVEL", cfg.Log.Level)
+
+	//-----------------------------------------
+	// Executor
+	//-----------------------------------------
+
+	ex := &cfg.Executor
+
+	ex.Backend = getEnv("EXECUTOR", ex.Backend)
+	ex.Namespace = getEnv("ARGO_NAMESPACE", ex.Namespace)
+	ex.DefaultCluster = getEnv("DEFAULT_CLUSTER", ex.DefaultCluster)
+	ex.ClusterConfigDir = getEnv("CLUSTER_KUBECONFIG_DIR", ex.ClusterConfigDir)
+	ex.SimulationScript = getEnv("FAKE_EXECUTOR_SCRIPT", ex.SimulationScript)
+
+	if raw := os.Getenv("ARGO_NAMESPACE_ROUTES"); raw != "" {
+		if ex.ServiceNamespaces, ex.TeamNamespaces, err = parseNamespaceRoutes(raw); err != nil {
+			return err
+		}
+	}
+
+	if raw := os.Getenv("CLUSTER_CONTEXTS"); raw != "" {
+		if ex.Clusters, err = parseClusterContexts(raw); err != nil {
+			return err
+		}
+	}
+
+	if raw := os.Getenv("CLUSTER_PLACEMENT"); raw != "" {
+		if ex.Placement, err = parsePlacement(raw); err != nil {
+			return err
+		}
+	}
+
+	if raw := os.Getenv("FAKE_TEMPLATE_DIRS"); raw != "" {
+		ex.SimulationTemplateDirs = splitList(raw)
+	}
+
+	//-----------------------------------------
+	// Providers
+	//-----------------------------------------
+
+	cfg.Providers.GitHubToken = getEnv("GITHUB_TOKEN", cfg.Providers.GitHubToken)
+
+	//-----------------------------------------
+	// Auth
+	//-----------------------------------------
+
+	a := &cfg.Auth
+
+	if a.Disabled, err = getBool("AUTH_DISABLED", a.Disabled); err != nil {
+		return err
+	}
+
+	a.TokensFile = getEnv("AUTH_TOKENS_FILE", a.TokensFile)
+	a.OIDC.Issuer = getEnv("OIDC_ISSUER", a.OIDC.Issuer)
+	a.OIDC.Audience = getEnv("OIDC_AUDIENCE", a.OIDC.Audience)
+	a.OIDC.JWKSURL = getEnv("OIDC_JWKS_URL", a.OIDC.JWKSURL)
+	a.OIDC.JWKSFile = getEnv("OIDC_JWKS_FILE", a.OIDC.JWKSFile)
+	a.OIDC.UsernameClaim = getEnv("OIDC_USERNAME_CLAIM", a.OIDC.UsernameClaim)
+	a.OIDC.GroupsClaim = getEnv("OIDC_GROUPS_CLAIM", a.OIDC.GroupsClaim)
+	a.ReadPolicy = getEnv("AUTHZ_READ_POLICY", a.ReadPolicy)
+
+	if raw := os.Getenv("AUTHZ_ADMIN_USERS"); raw != "" {
+		a.AdminUsers = splitList(raw)
+	}
+	if raw := os.Getenv("AUTHZ_ADMIN_GROUPS"); raw != "" {
+		a.AdminGroups = splitList(raw)
+	}
+
+	//-----------------------------------------
+	// Rate limits, audit, tracing
+	//-----------------------------------------
+
+	if err := applyRateLimitEnv(&cfg.RateLimit); err != nil {
+		return err
+	}
+
+	cfg.Audit.File = getEnv("AUDIT_LOG_FILE", cfg.Audit.File)
+
+	cfg.Tracing.Exporter = getEnv("TRACING_EXPORTER", cfg.Tracing.Exporter)
+	cfg.Tracing.File = getEnv("TRACING_FILE", cfg.Tracing.File)
+
+	if cfg.Tracing.SampleRatio, err = getFloat("TRACING_SAMPLE_RATIO", cfg.Tracing.SampleRatio); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func applyRateLimitEnv(cfg *RateLimitConfig) error {
+
+	var err error
+
+	if cfg.ClientRate, err = getFloat("RATE_LIMIT_CLIENT_RPS", cfg.ClientRate); err != nil {
+		return err
+	}
+	if cfg.ClientBurst, err = getInt("RATE_LIMIT_CLIENT_BURST", cfg.ClientBurst); err != nil {
+		return err
+	}
+	if cfg.ReadRate, err = getFloat("RATE_LIMIT_READ_RPS", cfg.ReadRate); err != nil {
+		return err
+	}
+	if cfg.ReadBurst, err = getInt("RATE_LIMIT_READ_BURST", cfg.ReadBurst); err != nil {
+		return err
+	}
+	if cfg.SubmitRate, err = getFloat("RATE_LIMIT_SUBMIT_RPS", cfg.SubmitRate); err != nil {
+		return err
 	}
+	if cfg.SubmitBurst, err = getInt("RATE_LIMIT_SUBMIT_BURST", cfg.SubmitBurst); err != nil {
+		return err
+	}
+	if cfg.MaxInFlightPerService, err = getInt("MAX_INFLIGHT_SUBMISSIONS_PER_SERVICE", cfg.MaxInFlightPerService); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func getEnv(key, fallback string) string {
@@ -27,3 +344,184 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getInt(key string, fallback int) (int, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback, nil
+	}
+
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+
+	return i, nil
+}
+
+func getFloat(key string, fallback float64) (float64, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback, nil
+	}
+
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+
+	return f, nil
+}
+
+func getDuration(key string, fallback Duration) (Duration, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return Duration{}, fmt.Errorf("%s: %w", key, err)
+	}
+
+	return Duration{d}, nil
+}
+
+func getBool(key string, fallback bool) (bool, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback, nil
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", key, err)
+	}
+
+	return b, nil
+}
+
+// splitList splits a comma separated list, dropping empty entries.
+func splitList(raw string) []string {
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// parseNamespaceRoutes parses ARGO_NAMESPACE_ROUTES.
+//
+// Format (comma separated):
+//
+//	service:<name>=<namespace>,team:<name>=<namespace>
+func parseNamespaceRoutes(raw string) (map[string]string, map[string]string, error) {
+
+	services := map[string]string{}
+	teams := map[string]string{}
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, namespace, ok := strings.Cut(entry, "=")
+		kind, name, hasKind := strings.Cut(key, ":")
+
+		if !ok || !hasKind || name == "" || namespace == "" {
+			return nil, nil, fmt.Errorf(
+				"ARGO_NAMESPACE_ROUTES: malformed route %q, want service:<name>=<namespace> or team:<name>=<namespace>",
+				entry,
+			)
+		}
+
+		switch kind {
+		case "service":
+			services[name] = namespace
+		case "team":
+			teams[name] = namespace
+		default:
+			return nil, nil, fmt.Errorf(
+				"ARGO_NAMESPACE_ROUTES: unknown route kind %q in %q",
+				kind,
+				entry,
+			)
+		}
+	}
+
+	return services, teams, nil
+}
+
+// parseClusterContexts parses CLUSTER_CONTEXTS.
+//
+// Format (comma separated):
+//
+//	<cluster>=<kubeconfig-context>
+func parseClusterContexts(raw string) ([]ClusterConfig, error) {
+
+	var clusters []ClusterConfig
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, context, ok := strings.Cut(entry, "=")
+		if !ok || name == "" || context == "" {
+			return nil, fmt.Errorf(
+				"CLUSTER_CONTEXTS: malformed entry %q, want <cluster>=<context>",
+				entry,
+			)
+		}
+
+		clusters = append(clusters, ClusterConfig{Name: name, Context: context})
+	}
+
+	return clusters, nil
+}
+
+// parsePlacement parses CLUSTER_PLACEMENT.
+//
+// Format (comma separated, first match wins):
+//
+//	service:<glob>=<cluster>,team:<name>=<cluster>
+func parsePlacement(raw string) ([]PlacementConfig, error) {
+
+	var rules []PlacementConfig
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, cluster, ok := strings.Cut(entry, "=")
+		kind, name, hasKind := strings.Cut(key, ":")
+
+		if !ok || !hasKind || name == "" || cluster == "" {
+			return nil, fmt.Errorf(
+				"CLUSTER_PLACEMENT: malformed rule %q, want service:<glob>=<cluster> or team:<name>=<cluster>",
+				entry,
+			)
+		}
+
+		switch kind {
+		case "service":
+			rules = append(rules, PlacementConfig{Service: name, Cluster: cluster})
+		case "team":
+			rules = append(rules, PlacementConfig{Team: name, Cluster: cluster})
+		default:
+			return nil, fmt.Errorf(
+				"CLUSTER_PLACEMENT: unknown rule kind %q in %q",
+				kind,
+				entry,
+			)
+		}
+	}
+
+	return rules, nil
+}
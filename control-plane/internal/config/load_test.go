@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		check   func(t *testing.T, c *Config)
+		wantErr []string
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"AUTH_DISABLED": "true"},
+			check: func(t *testing.T, c *Config) {
+				if c.HTTP.Address != ":8080" || c.Executor.Namespace != "argo" {
+					t.Fatalf("defaults = %+v", c)
+				}
+			},
+		},
+		{
+			name: "file over defaults",
+			file: "http:\n  address: \":9090\"\nauth:\n  disabled: true\nenvironments:\n  max_ttl: 72h\n",
+			check: func(t *testing.T, c *Config) {
+				if c.HTTP.Address != ":9090" || c.HTTP.ReadTimeout.Duration != 5*time.Second {
+					t.Fatalf("http = %+v", c.HTTP)
+				}
+				if c.Environments.MaxTTL.Duration != 72*time.Hour {
+					t.Fatalf("environments = %+v", c.Environments)
+				}
+			},
+		},
+		{
+			name: "environment over file",
+			file: "http:\n  address: \":9090\"\nauth:\n  disabled: true\n",
+			env: map[string]string{
+				"HTTP_ADDRESS":          ":7070",
+				"ARGO_NAMESPACE_ROUTES": "service:payments=payments-ci, team:search=search-ci",
+				"AUTHZ_ADMIN_USERS":     "alice, bob",
+			},
+			check: func(t *testing.T, c *Config) {
+				if c.HTTP.Address != ":7070" {
+					t.Fatalf("address = %q", c.HTTP.Address)
+				}
+				if c.Executor.ServiceNamespaces["payments"] != "payments-ci" || c.Executor.TeamNamespaces["search"] != "search-ci" {
+					t.Fatalf("routes = %v, %v", c.Executor.ServiceNamespaces, c.Executor.TeamNamespaces)
+				}
+				if strings.Join(c.Auth.AdminUsers, ",") != "alice,bob" {
+					t.Fatalf("admin users = %v", c.Auth.AdminUsers)
+				}
+			},
+		},
+		{
+			name:    "unknown keys and bad durations by full key",
+			file:    "htp:\n  address: x\nhttp:\n  read_timeout: 5 minutes\n  tls:\n    cert: x\n",
+			wantErr: []string{"htp: unknown key", `http.read_timeout: invalid duration "5 minutes"`, "http.tls.cert: unknown key"},
+		},
+		{
+			name:    "malformed environment",
+			env:     map[string]string{"AUTH_DISABLED": "true", "RATE_LIMIT_CLIENT_RPS": "fast"},
+			wantErr: []string{"RATE_LIMIT_CLIENT_RPS"},
+		},
+		{
+			name:    "malformed routes",
+			env:     map[string]string{"AUTH_DISABLED": "true", "ARGO_NAMESPACE_ROUTES": "payments=ci"},
+			wantErr: []string{"ARGO_NAMESPACE_ROUTES: malformed route"},
+		},
+		{
+			name:    "validated",
+			file:    "log:\n  level: loud\n",
+			wantErr: []string{"log.level", "auth: one of tokens_file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var path string
+			if tt.file != "" {
+				path = filepath.Join(t.TempDir(), "config.yaml")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c, err := Load(path)
+
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("Load() succeeded")
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Fatalf("Load() = %v, want %q", err, want)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, c)
+		})
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+
+	c, err := Load("../../config.example.yaml")
+	if err != nil {
+		t.Fatalf("config.example.yaml: %v", err)
+	}
+
+	if _, ok := c.Blueprints["namespace-postgres"]; !ok {
+		t.Fatalf("blueprints = %v", c.Blueprints)
+	}
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+)
+
+// Validate reports every problem at once, each prefixed with the
+// config file key it concerns, e.g.
+//
+//	invalid configuration:
+//	  http.read_timeout: must be positive
+//	  executor.backend: unknown backend "argp" (want argo, jobs or fake)
+func (c *Config) Validate() error {
+
+	v := &validator{}
+
+	//-----------------------------------------
+	// HTTP
+	//-----------------------------------------
+
+	v.check(c.HTTP.Address != "", "http.address", "is required")
+	v.positive("http.read_timeout", c.HTTP.ReadTimeout)
+	v.positive("http.write_timeout", c.HTTP.WriteTimeout)
+	v.positive("http.shutdown_timeout", c.HTTP.ShutdownTimeout)
+	v.nonNegative("http.idle_timeout", c.HTTP.IdleTimeout)
+
+	if c.HTTP.TLS.Enabled() {
+		v.check(c.HTTP.TLS.CertFile != "", "http.tls.cert_file", "is required with http.tls.key_file")
+		v.check(c.HTTP.TLS.KeyFile != "", "http.tls.key_file", "is required with http.tls.cert_file")
+	}
+
+	if _, err := zapcore.ParseLevel(c.Log.Level); err != nil {
+		v.add("log.level", "unknown level %q (want debug, info, warn or error)", c.Log.Level)
+	}
+
+	//-----------------------------------------
+	// Executor
+	//-----------------------------------------
+
+	switch c.Executor.Backend {
+	case BackendArgo, BackendJobs, BackendFake:
+	default:
+		v.add(
+			"executor.backend",
+			"unknown backend %q (want %s, %s or %s)",
+			c.Executor.Backend,
+			BackendArgo,
+			BackendJobs,
+			BackendFake,
+		)
+	}
+
+	v.check(c.Executor.Namespace != "", "executor.namespace", "is required")
+	v.check(c.Executor.DefaultCluster != "", "executor.default_cluster", "is required")
+
+	clusters := map[string]bool{c.Executor.DefaultCluster: true}
+
+	for i, cl := range c.Executor.Clusters {
+		key := fmt.Sprintf("executor.clusters[%d]", i)
+		v.check(cl.Name != "", key+".name", "is required")
+		v.check(cl.Context != "", key+".context", "is required")
+		v.check(!clusters[cl.Name], key+".name", "cluster %q defined twice", cl.Name)
+		clusters[cl.Name] = true
+	}
+
+	for i, p := range c.Executor.Placement {
+		key := fmt.Sprintf("executor.placement[%d]", i)
+		v.check((p.Service == "") != (p.Team == ""), key, "exactly one of service or team is required")
+		v.check(p.Cluster != "", key+".cluster", "is required")
+
+		if _, err := path.Match(p.Service, ""); err != nil {
+			v.add(key+".service", "invalid glob %q", p.Service)
+		}
+
+		// Clusters from cluster_config_dir are only known at startup.
+		if p.Cluster != "" && c.Executor.ClusterConfigDir == "" {
+			v.check(clusters[p.Cluster], key+".cluster", "unknown cluster %q", p.Cluster)
+		}
+	}
+
+	//-----------------------------------------
+	// Templates and policy
+	//-----------------------------------------
+
+	v.positive("templates.refresh_interval", c.Templates.RefreshInterval)
+
+	for _, key := range sortedKeys(c.Templates.Pinned) {
+		if _, _, err := catalog.ParsePinKey(key); err != nil {
+			v.add("templates.pinned."+key, "%v", err)
+		}
+		v.check(c.Templates.Pinned[key] != "", "templates.pinned."+key, "template name is required")
+	}
+
+	v.nonNegative("environments.default_ttl", c.Environments.DefaultTTL)
+	v.nonNegative("environments.max_ttl", c.Environments.MaxTTL)
+
+	if max := c.Environments.MaxTTL.Duration; max > 0 && c.Environments.DefaultTTL.Duration > max {
+		v.add("environments.default_ttl", "exceeds environments.max_ttl (%s)", max)
+	}
+
+	_, configured := c.Blueprints[c.Environments.DefaultBlueprint]
+	v.check(
+		c.Environments.DefaultBlueprint == blueprint.Default || configured,
+		"environments.default_blueprint",
+		"unknown blueprint %q",
+		c.Environments.DefaultBlueprint,
+	)
+
+	for _, name := range sortedKeys(c.Blueprints) {
+		c.Blueprints[name].validate(v, "blueprints."+name, name, c.Environments.MaxTTL)
+	}
+
+	q := c.Quotas.Defaults
+	v.check(q.MaxEnvironments >= 0, "quotas.defaults.max_environments", "must not be negative")
+	v.nonNegative("quotas.defaults.max_ttl", q.MaxTTL)
+	v.check(q.MaxConcurrentRuns >= 0, "quotas.defaults.max_concurrent_runs", "must not be negative")
+	v.check(q.MaxEnvironmentHoursPerMonth >= 0, "quotas.defaults.max_environment_hours_per_month", "must not be negative")
+
+	//-----------------------------------------
+	// Auth
+	//-----------------------------------------
+
+	if !c.Auth.Disabled {
+		v.check(
+			c.Auth.TokensFile != "" || c.Auth.OIDC.Issuer != "",
+			"auth",
+			"one of tokens_file (AUTH_TOKENS_FILE) or oidc.issuer (OIDC_ISSUER) is required unless disabled (AUTH_DISABLED) is set",
+		)
+	}
+
+	switch c.Auth.ReadPolicy {
+	case "authenticated", "owner":
+	default:
+		v.add("auth.read_policy", "unknown policy %q (want authenticated or owner)", c.Auth.ReadPolicy)
+	}
+
+	if c.Auth.OIDC.Issuer != "" {
+		v.check(c.Auth.OIDC.UsernameClaim != "", "auth.oidc.username_claim", "is required")
+		v.check(c.Auth.OIDC.GroupsClaim != "", "auth.oidc.groups_claim", "is required")
+	}
+
+	//-----------------------------------------
+	// Rate limits and tracing
+	//-----------------------------------------
+
+	rl := c.RateLimit
+	v.check(rl.ClientRate >= 0, "rate_limit.client_rps", "must not be negative")
+	v.check(rl.ReadRate >= 0, "rate_limit.read_rps", "must not be negative")
+	v.check(rl.SubmitRate >= 0, "rate_limit.submit_rps", "must not be negative")
+	v.check(rl.ClientRate == 0 || rl.ClientBurst > 0, "rate_limit.client_burst", "must be positive")
+	v.check(rl.ReadRate == 0 || rl.ReadBurst > 0, "rate_limit.read_burst", "must be positive")
+	v.check(rl.SubmitRate == 0 || rl.SubmitBurst > 0, "rate_limit.submit_burst", "must be positive")
+	v.check(rl.MaxInFlightPerService >= 0, "rate_limit.max_inflight_per_service", "must not be negative")
+
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	case "file":
+		v.check(c.Tracing.File != "", "tracing.file", "is required with exporter file")
+	default:
+		v.add("tracing.exporter", "unknown exporter %q (want none, otlp, stdout or file)", c.Tracing.Exporter)
+	}
+
+	v.check(
+		c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"tracing.sample_ratio",
+		"must be between 0 and 1",
+	)
+
+	return v.err()
+}
+
+//
+// ---- Helpers ----
+//
+
+func (b BlueprintConfig) validate(
+	v *validator,
+	key string,
+	name string,
+	maxTTL Duration,
+) {
+
+	v.check(blueprint.ValidName(name), key, "blueprint names must be DNS labels")
+	v.nonNegative(key+".default_ttl", b.DefaultTTL)
+
+	if maxTTL.Duration > 0 && b.DefaultTTL.Duration > maxTTL.Duration {
+		v.add(key+".default_ttl", "exceeds environments.max_ttl (%s)", maxTTL.Duration)
+	}
+
+	if tf := b.Terraform; tf != nil {
+		v.check(tf.Module != "", key+".terraform.module", "is required")
+		v.check(tf.Version != "", key+".terraform.version", "is required, so that destroy uses the module version apply used")
+		v.check(b.CreateTemplate != "", key+".create_template", "is required for terraform blueprints")
+		v.check(b.DestroyTemplate != "", key+".destroy_template", "is required for terraform blueprints")
+		v.check(b.TTLTemplate != "", key+".ttl_template", "is required for terraform blueprints")
+	}
+
+	outputs := make(map[string]bool, len(b.Outputs))
+
+	for i, o := range b.Outputs {
+		okey := fmt.Sprintf("%s.outputs[%d]", key, i)
+		v.check(o.Name != "", okey+".name", "is required")
+		v.check(!outputs[o.Name], okey+".name", "output %q defined twice", o.Name)
+		v.check(
+			blueprint.ValidOutputType(blueprint.OutputType(o.Type)),
+			okey+".type",
+			"unknown type %q (want %s, %s or %s)",
+			o.Type,
+			blueprint.OutputString,
+			blueprint.OutputURL,
+			blueprint.OutputSecret,
+		)
+		outputs[o.Name] = true
+	}
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) add(key, format string, args ...interface{}) {
+	v.problems = append(v.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) check(ok bool, key, format string, args ...interface{}) {
+	if !ok {
+		v.add(key, format, args...)
+	}
+}
+
+func (v *validator) positive(key string, d Duration) {
+	v.check(d.Duration > 0, key, "must be positive")
+}
+
+func (v *validator) nonNegative(key string, d Duration) {
+	v.check(d.Duration >= 0, key, "must not be negative")
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n  %s", strings.Join(v.problems, "\n  "))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+
+	c := Default()
+	c.Auth.Disabled = true
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() of the defaults = %v", err)
+	}
+
+	c.HTTP.ReadTimeout = Duration{}
+	c.Executor.Backend = "argp"
+	c.Executor.Placement = []PlacementConfig{{Service: "pay-*", Cluster: "mars"}}
+	c.Templates.Pinned = map[string]string{"build": "x", "ci/go": ""}
+	c.Environments.DefaultTTL = Duration{96 * time.Hour}
+	c.Environments.MaxTTL = Duration{72 * time.Hour}
+	c.Environments.DefaultBlueprint = "full-stack"
+	c.Blueprints = BlueprintsConfig{
+		"namespace+postgres": {},
+		"tf": {
+			Terraform: &TerraformConfig{Module: "git::https://example.com/m.git"},
+			Outputs:   []OutputConfig{{Name: "url", Type: "link"}, {Name: "url", Type: "url"}},
+		},
+	}
+	c.Quotas.Defaults.MaxEnvironments = -1
+	c.Auth.ReadPolicy = "everyone"
+	c.RateLimit.SubmitBurst = 0
+	c.Tracing.Exporter = "file"
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Validate() succeeded")
+	}
+
+	for _, want := range []string{
+		"http.read_timeout: must be positive",
+		`executor.backend: unknown backend "argp"`,
+		`executor.placement[0].cluster: unknown cluster "mars"`,
+		`templates.pinned.build: unknown purpose "build"`,
+		"templates.pinned.ci/go: template name is required",
+		"environments.default_ttl: exceeds environments.max_ttl",
+		`environments.default_blueprint: unknown blueprint "full-stack"`,
+		"blueprints.namespace+postgres: blueprint names must be DNS labels",
+		"blueprints.tf.terraform.version: is required",
+		"blueprints.tf.create_template: is required for terraform blueprints",
+		`blueprints.tf.outputs[0].type: unknown type "link"`,
+		`blueprints.tf.outputs[1].name: output "url" defined twice`,
+		"quotas.defaults.max_environments: must not be negative",
+		`auth.read_policy: unknown policy "everyone"`,
+		"rate_limit.submit_burst: must be positive",
+		"tracing.file: is required with exporter file",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Validate() = %v\nwant %q", err, want)
+		}
+	}
+}
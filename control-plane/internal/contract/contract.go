@@ -0,0 +1,114 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+)
+
+// ErrViolation is returned when submitted parameters do not satisfy
+// the parameters a template declares.
+//
+// Without the check, a template drifting from the control plane only
+// shows up as a failed workflow minutes after submission.
+var ErrViolation = errors.New("template parameter contract violated")
+
+// Result compares the parameters a template declares in
+// spec.arguments.parameters with the parameters submitted to it.
+//
+// Missing and invalid parameters fail a submission. Undeclared ones
+// are passed through and only warned about: Argo ignores them, but
+// they usually mean the template and the control plane disagree.
+type Result struct {
+	Template   string
+	Missing    []string
+	Invalid    []string
+	Undeclared []string
+}
+
+// Check compares submitted parameter values with declared parameters.
+func Check(
+	template string,
+	declared []catalog.Parameter,
+	submitted map[string]string,
+) Result {
+
+	names := make([]string, 0, len(submitted))
+	for name := range submitted {
+		names = append(names, name)
+	}
+
+	res := compare(template, declared, names)
+
+	for _, p := range declared {
+		v, ok := submitted[p.Name]
+		if !ok || len(p.Enum) == 0 || slices.Contains(p.Enum, v) {
+			continue
+		}
+		res.Invalid = append(res.Invalid, fmt.Sprintf(
+			"%s=%q (want one of %s)",
+			p.Name,
+			v,
+			strings.Join(p.Enum, ", "),
+		))
+	}
+
+	return res
+}
+
+// Err reports missing and invalid parameters as an ErrViolation.
+func (r Result) Err() error {
+
+	var problems []string
+	if len(r.Missing) > 0 {
+		problems = append(problems, "missing required parameters: "+strings.Join(r.Missing, ", "))
+	}
+	if len(r.Invalid) > 0 {
+		problems = append(problems, "invalid parameters: "+strings.Join(r.Invalid, ", "))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: template %s: %s", ErrViolation, r.Template, strings.Join(problems, "; "))
+}
+
+//
+// ---- Helpers ----
+//
+
+// compare matches parameter names only; values are not always known
+// (see Preflight).
+func compare(
+	template string,
+	declared []catalog.Parameter,
+	submitted []string,
+) Result {
+
+	res := Result{Template: template}
+
+	isDeclared := make(map[string]bool, len(declared))
+	for _, p := range declared {
+		isDeclared[p.Name] = true
+
+		if p.Required && !slices.Contains(submitted, p.Name) {
+			res.Missing = append(res.Missing, p.Name)
+		}
+	}
+
+	for _, name := range submitted {
+		if !isDeclared[name] {
+			res.Undeclared = append(res.Undeclared, name)
+		}
+	}
+
+	sort.Strings(res.Missing)
+	sort.Strings(res.Undeclared)
+
+	return res
+}
@@ -0,0 +1,94 @@
+package contract
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+)
+
+func TestCheck(t *testing.T) {
+
+	declared := []catalog.Parameter{
+		{Name: "service", Required: true},
+		{Name: "revision", Required: true},
+		{Name: "mode", Enum: []string{"fast", "full"}},
+		{Name: "verbose"},
+	}
+
+	tests := []struct {
+		name           string
+		submitted      map[string]string
+		wantMissing    []string
+		wantInvalid    []string
+		wantUndeclared []string
+		wantErr        string
+	}{
+		{
+			name:      "all required",
+			submitted: map[string]string{"service": "payments", "revision": "abc"},
+		},
+		{
+			name:      "enum value allowed",
+			submitted: map[string]string{"service": "payments", "revision": "abc", "mode": "full"},
+		},
+		{
+			name:        "missing required",
+			submitted:   map[string]string{"verbose": "true"},
+			wantMissing: []string{"revision", "service"},
+			wantErr:     "missing required parameters: revision, service",
+		},
+		{
+			name:        "enum value not allowed",
+			submitted:   map[string]string{"service": "payments", "revision": "abc", "mode": "slow"},
+			wantInvalid: []string{`mode="slow" (want one of fast, full)`},
+			wantErr:     "invalid parameters",
+		},
+		{
+			name:           "undeclared only warns",
+			submitted:      map[string]string{"service": "payments", "revision": "abc", "zeta": "1", "alpha": "2"},
+			wantUndeclared: []string{"alpha", "zeta"},
+		},
+		{
+			name:        "missing and invalid",
+			submitted:   map[string]string{"service": "payments", "mode": "slow"},
+			wantMissing: []string{"revision"},
+			wantInvalid: []string{`mode="slow" (want one of fast, full)`},
+			wantErr:     "missing required parameters: revision; invalid parameters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Check("ci-template", declared, tt.submitted)
+
+			if !slices.Equal(res.Missing, tt.wantMissing) {
+				t.Fatalf("Missing = %v, want %v", res.Missing, tt.wantMissing)
+			}
+			if !slices.Equal(res.Invalid, tt.wantInvalid) {
+				t.Fatalf("Invalid = %v, want %v", res.Invalid, tt.wantInvalid)
+			}
+			if !slices.Equal(res.Undeclared, tt.wantUndeclared) {
+				t.Fatalf("Undeclared = %v, want %v", res.Undeclared, tt.wantUndeclared)
+			}
+
+			err := res.Err()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Err() = %v, want nil", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrViolation) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Err() = %v, want %q", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), "template ci-template") {
+				t.Fatalf("Err() = %v, want the template named", err)
+			}
+		})
+	}
+}
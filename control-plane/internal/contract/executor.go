@@ -0,0 +1,79 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/logging"
+)
+
+// Executor checks every template submission against the template's
+// declared parameters before it reaches the execution plane.
+//
+// The template is read live from the submission target, not from the
+// catalog: routed namespaces and other clusters carry their own copy,
+// and that copy is the one Argo will run.
+//
+// Generated workflows, reads and cancellations pass through.
+type Executor struct {
+	executor.WorkflowExecutor
+
+	templates executor.TemplateReader
+	logger    *zap.Logger
+}
+
+func NewExecutor(
+	inner executor.WorkflowExecutor,
+	templates executor.TemplateReader,
+	logger *zap.Logger,
+) *Executor {
+	return &Executor{
+		WorkflowExecutor: inner,
+		templates:        templates,
+		logger:           logger,
+	}
+}
+
+func (e *Executor) SubmitFromTemplate(
+	ctx context.Context,
+	target executor.Target,
+	templateName string,
+	generateName string,
+	parameters map[string]string,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	template, err := e.templates.GetTemplate(ctx, target, templateName)
+	if err != nil {
+		return nil, fmt.Errorf("check template parameters: %w", err)
+	}
+
+	res := Check(templateName, catalog.ParametersOf(template), parameters)
+
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(res.Undeclared) > 0 {
+		logging.FromContext(ctx, e.logger).Warn("submitting parameters the template does not declare",
+			zap.String("template", templateName),
+			zap.String("cluster", target.Cluster),
+			zap.String("namespace", target.Namespace),
+			zap.Strings("parameters", res.Undeclared),
+		)
+	}
+
+	return e.WorkflowExecutor.SubmitFromTemplate(
+		ctx,
+		target,
+		templateName,
+		generateName,
+		parameters,
+		labels,
+	)
+}
@@ -0,0 +1,67 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+// Expectation is a template and the parameters the platform submits
+// to it.
+type Expectation struct {
+	Template   string
+	Parameters []string
+
+	// CallerSupplied is set when callers add parameters of their own
+	// (CI runs). Required parameters the platform does not submit are
+	// then the caller's to supply and are not reported as missing.
+	CallerSupplied bool
+}
+
+// Finding is the outcome of checking one template in one target.
+// Err is set when the template could not be read.
+type Finding struct {
+	Target executor.Target
+	Result Result
+	Err    error
+}
+
+// Preflight checks every expectation against the template installed
+// in every target, so drift is reported at startup rather than on the
+// first submission. Only findings with a problem are returned.
+func Preflight(
+	ctx context.Context,
+	templates executor.TemplateReader,
+	targets []executor.Target,
+	expectations []Expectation,
+) []Finding {
+
+	var findings []Finding
+
+	for _, target := range targets {
+		for _, exp := range expectations {
+
+			template, err := templates.GetTemplate(ctx, target, exp.Template)
+			if err != nil {
+				findings = append(findings, Finding{
+					Target: target,
+					Result: Result{Template: exp.Template},
+					Err:    err,
+				})
+				continue
+			}
+
+			res := compare(exp.Template, catalog.ParametersOf(template), exp.Parameters)
+			if exp.CallerSupplied {
+				res.Missing = nil
+			}
+
+			if len(res.Missing) > 0 || len(res.Undeclared) > 0 {
+				findings = append(findings, Finding{Target: target, Result: res})
+			}
+		}
+	}
+
+	return findings
+}
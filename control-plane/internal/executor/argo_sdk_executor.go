@@ -12,24 +12,24 @@ import (
 // Compile-time enforcement.
 // If the interface changes, this fails the build immediately.
 var _ WorkflowExecutor = (*ArgoSDKExecutor)(nil)
+var _ TemplateReader = (*ArgoSDKExecutor)(nil)
+var _ WorkflowLister = (*ArgoSDKExecutor)(nil)
 
 type ArgoSDKExecutor struct {
-	clients   *Clients
-	namespace string
+	clients *Clients
 }
 
 func NewArgoSDKExecutor(
 	clients *Clients,
-	namespace string,
 ) *ArgoSDKExecutor {
 	return &ArgoSDKExecutor{
-		clients:   clients,
-		namespace: namespace,
+		clients: clients,
 	}
 }
 
 func (e *ArgoSDKExecutor) SubmitFromTemplate(
 	ctx context.Context,
+	target Target,
 	templateName string,
 	generateName string,
 	parameters map[string]string,
@@ -40,6 +40,8 @@ func (e *ArgoSDKExecutor) SubmitFromTemplate(
 	// Build parameters
 	//-----------------------------------------
 
+	parameters = withTraceParameter(ctx, parameters)
+
 	args := wf.Arguments{}
 
 	for k, v := range parameters {
@@ -55,15 +57,9 @@ func (e *ArgoSDKExecutor) SubmitFromTemplate(
 	// Build labels OUTSIDE the struct literal
 	//-----------------------------------------
 
-	mergedLabels := map[string]string{
-		"platform.control-plane":     "true",
-		"platform.executor":          "argo",
-		"platform.workflow.template": templateName,
-	}
-
-	// Caller labels override ONLY non-platform keys.
-	for k, v := range labels {
-		mergedLabels[k] = v
+	mergedLabels, annotations, err := applyLabelContract(ctx, "argo", templateName, labels)
+	if err != nil {
+		return nil, fmt.Errorf("submit workflow from template %s: %w", templateName, err)
 	}
 
 	//-----------------------------------------
@@ -74,6 +70,7 @@ func (e *ArgoSDKExecutor) SubmitFromTemplate(
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: generateName,
 			Labels:       mergedLabels,
+			Annotations:  mergeAnnotations(annotations),
 		},
 		Spec: wf.WorkflowSpec{
 			WorkflowTemplateRef: &wf.WorkflowTemplateRef{
@@ -83,10 +80,15 @@ func (e *ArgoSDKExecutor) SubmitFromTemplate(
 		},
 	}
 
-	created, err := e.clients.
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := cluster.
 		Argo.
 		ArgoprojV1alpha1().
-		Workflows(e.namespace).
+		Workflows(target.Namespace).
 		Create(ctx, workflow, metav1.CreateOptions{})
 
 	if err != nil {
@@ -96,15 +98,57 @@ func (e *ArgoSDKExecutor) SubmitFromTemplate(
 	return created, nil
 }
 
+func (e *ArgoSDKExecutor) SubmitWorkflow(
+	ctx context.Context,
+	target Target,
+	workflow *wf.Workflow,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	mergedLabels, annotations, err := applyLabelContract(ctx, "argo", GeneratedTemplate, labels)
+	if err != nil {
+		return nil, fmt.Errorf("submit generated workflow %s: %w", workflow.GenerateName, err)
+	}
+
+	submitted := workflow.DeepCopy()
+	addTraceArgument(ctx, submitted)
+	submitted.Namespace = target.Namespace
+	submitted.Labels = mergedLabels
+	submitted.Annotations = mergeAnnotations(submitted.Annotations, annotations)
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := cluster.
+		Argo.
+		ArgoprojV1alpha1().
+		Workflows(target.Namespace).
+		Create(ctx, submitted, metav1.CreateOptions{})
+
+	if err != nil {
+		return nil, fmt.Errorf("submit generated workflow %s: %w", workflow.GenerateName, err)
+	}
+
+	return created, nil
+}
+
 func (e *ArgoSDKExecutor) GetWorkflow(
 	ctx context.Context,
+	target Target,
 	name string,
 ) (*wf.Workflow, error) {
 
-	w, err := e.clients.
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := cluster.
 		Argo.
 		ArgoprojV1alpha1().
-		Workflows(e.namespace).
+		Workflows(target.Namespace).
 		Get(ctx, name, metav1.GetOptions{})
 
 	if err != nil {
@@ -116,15 +160,21 @@ func (e *ArgoSDKExecutor) GetWorkflow(
 
 func (e *ArgoSDKExecutor) Cancel(
 	ctx context.Context,
+	target Target,
 	name string,
 ) error {
 
 	patch := []byte(`{"spec":{"shutdown":"Terminate"}}`)
 
-	_, err := e.clients.
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return err
+	}
+
+	_, err = cluster.
 		Argo.
 		ArgoprojV1alpha1().
-		Workflows(e.namespace).
+		Workflows(target.Namespace).
 		Patch(
 			ctx,
 			name,
@@ -139,3 +189,88 @@ func (e *ArgoSDKExecutor) Cancel(
 
 	return nil
 }
+
+func (e *ArgoSDKExecutor) ListTemplates(
+	ctx context.Context,
+	target Target,
+	labelSelector string,
+) ([]wf.WorkflowTemplate, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cluster.
+		Argo.
+		ArgoprojV1alpha1().
+		WorkflowTemplates(target.Namespace).
+		List(ctx, metav1.ListOptions{
+			LabelSelector: labelSelector,
+		})
+
+	if err != nil {
+		return nil, fmt.Errorf("list workflow templates: %w", err)
+	}
+
+	return list.Items, nil
+}
+
+func (e *ArgoSDKExecutor) GetTemplate(
+	ctx context.Context,
+	target Target,
+	name string,
+) (*wf.WorkflowTemplate, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := cluster.
+		Argo.
+		ArgoprojV1alpha1().
+		WorkflowTemplates(target.Namespace).
+		Get(ctx, name, metav1.GetOptions{})
+
+	if err != nil {
+		return nil, fmt.Errorf("get workflow template %s: %w", name, err)
+	}
+
+	return template, nil
+}
+
+func (e *ArgoSDKExecutor) ListWorkflows(
+	ctx context.Context,
+	target Target,
+	opts ListOptions,
+) (*WorkflowList, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cluster.
+		Argo.
+		ArgoprojV1alpha1().
+		Workflows(target.Namespace).
+		List(ctx, metav1.ListOptions{
+			LabelSelector: opts.LabelSelector,
+			Limit:         opts.Limit,
+			Continue:      opts.Continue,
+		})
+
+	if err != nil {
+		return nil, fmt.Errorf("list workflows: %w", err)
+	}
+
+	return &WorkflowList{
+		Items:    list.Items,
+		Continue: list.Continue,
+	}, nil
+}
+
+// GeneratedTemplate is the template label value for workflows
+// submitted inline rather than from a WorkflowTemplate.
+const GeneratedTemplate = "generated"
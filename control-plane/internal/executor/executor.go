@@ -17,6 +17,10 @@ import (
 // It does NOT orchestrate.
 //
 // Argo owns execution semantics.
+//
+// Every call names its Target (cluster + namespace). The executor
+// holds no placement of its own, so one executor serves every tenant
+// and every cluster.
 type WorkflowExecutor interface {
 
 	// SubmitFromTemplate creates a Workflow CR from a WorkflowTemplate.
@@ -32,18 +36,32 @@ type WorkflowExecutor interface {
 	//   - UID
 	SubmitFromTemplate(
 		ctx context.Context,
+		target Target,
 		templateName string,
 		generateName string,
 		parameters map[string]string,
 		labels map[string]string,
 	) (*wf.Workflow, error)
 
+	// SubmitWorkflow creates a fully specified Workflow CR.
+	//
+	// Used for generated pipelines that have no WorkflowTemplate.
+	// The executor sets platform labels; the
+	// caller's spec is otherwise submitted unchanged.
+	SubmitWorkflow(
+		ctx context.Context,
+		target Target,
+		workflow *wf.Workflow,
+		labels map[string]string,
+	) (*wf.Workflow, error)
+
 	// GetWorkflow retrieves the live workflow object from Argo.
 	//
 	// IMPORTANT:
 	// This is a READ — not state ownership.
 	GetWorkflow(
 		ctx context.Context,
+		target Target,
 		name string,
 	) (*wf.Workflow, error)
 
@@ -55,6 +73,7 @@ type WorkflowExecutor interface {
 	// This preserves Argo as the execution authority.
 	Cancel(
 		ctx context.Context,
+		target Target,
 		name string,
 	) error
 }
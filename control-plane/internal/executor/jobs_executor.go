@@ -0,0 +1,588 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var _ WorkflowExecutor = (*JobsExecutor)(nil)
+var _ TemplateReader = (*JobsExecutor)(nil)
+var _ WorkflowLister = (*JobsExecutor)(nil)
+
+// JobTemplateKey is the ConfigMap key holding a job template.
+//
+// A job template is an ordinary WorkflowTemplate manifest whose
+// entrypoint is a single container or script template. Keeping the
+// Argo shape means the same file can be applied to Argo clusters as-is
+// and to Argo-free clusters wrapped in a ConfigMap:
+//
+//	apiVersion: v1
+//	kind: ConfigMap
+//	metadata:
+//	  name: env-create-template
+//	  labels:
+//	    platform.catalog: "true"
+//	    platform.template.purpose: env-create
+//	data:
+//	  workflowtemplate.yaml: |
+//	    <WorkflowTemplate manifest>
+//
+// The ConfigMap's name and labels take precedence over the embedded
+// manifest's, so discovery works exactly as it does for Argo.
+const JobTemplateKey = "workflowtemplate.yaml"
+
+// Annotation carrying the submitted parameters, so that a Job can be
+// reported back with the same arguments a Workflow would have.
+const annotationParameters = "platform.workflow.parameters"
+
+// ErrUnsupportedWorkflow is returned for workflows the Jobs backend
+// cannot express as a single Job (DAGs, steps, suspend...).
+var ErrUnsupportedWorkflow = errors.New("workflow not supported by jobs executor")
+
+// JobsExecutor runs workflows as plain batch/v1 Jobs.
+//
+// It is the backend for clusters that cannot run Argo.
+// Only single-container workflows are supported; that covers the
+// environment lifecycle templates, not generated pipelines.
+//
+// Job state is translated into the Workflow status shape the
+// orchestrators already understand, so nothing above this layer
+// knows which backend ran the work.
+type JobsExecutor struct {
+	clients *Clients
+}
+
+func NewJobsExecutor(
+	clients *Clients,
+) *JobsExecutor {
+	return &JobsExecutor{
+		clients: clients,
+	}
+}
+
+func (e *JobsExecutor) SubmitFromTemplate(
+	ctx context.Context,
+	target Target,
+	templateName string,
+	generateName string,
+	parameters map[string]string,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := e.getTemplate(ctx, cluster, target, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolveParameters(
+		template.Spec.Arguments.Parameters,
+		withTraceParameter(ctx, parameters),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("submit job from template %s: %w", templateName, err)
+	}
+
+	workflow := &wf.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+		},
+		Spec: template.Spec,
+	}
+	workflow.Spec.Arguments = wf.Arguments{}
+	for _, name := range sortedKeys(resolved) {
+		workflow.Spec.Arguments.Parameters = append(workflow.Spec.Arguments.Parameters, wf.Parameter{
+			Name:  name,
+			Value: wf.AnyStringPtr(resolved[name]),
+		})
+	}
+
+	return e.submit(ctx, cluster, target, templateName, workflow, labels)
+}
+
+func (e *JobsExecutor) SubmitWorkflow(
+	ctx context.Context,
+	target Target,
+	workflow *wf.Workflow,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	submitted := workflow.DeepCopy()
+	addTraceArgument(ctx, submitted)
+
+	return e.submit(ctx, cluster, target, GeneratedTemplate, submitted, labels)
+}
+
+func (e *JobsExecutor) GetWorkflow(
+	ctx context.Context,
+	target Target,
+	name string,
+) (*wf.Workflow, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := cluster.Kube.BatchV1().
+		Jobs(target.Namespace).
+		Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get job %s: %w", name, err)
+	}
+
+	return toWorkflow(job), nil
+}
+
+// Cancel deletes the Job and its pods.
+//
+// Unlike Argo there is no terminated record left behind;
+// subsequent GetWorkflow calls report NotFound.
+func (e *JobsExecutor) Cancel(
+	ctx context.Context,
+	target Target,
+	name string,
+) error {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return err
+	}
+
+	propagation := metav1.DeletePropagationBackground
+
+	if err := cluster.Kube.BatchV1().
+		Jobs(target.Namespace).
+		Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+		return fmt.Errorf("delete job %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// ListTemplates returns job templates as WorkflowTemplates so the
+// catalog can treat both backends alike.
+//
+// ConfigMaps matching the selector but lacking JobTemplateKey are
+// ignored; malformed templates fail the listing.
+func (e *JobsExecutor) ListTemplates(
+	ctx context.Context,
+	target Target,
+	labelSelector string,
+) ([]wf.WorkflowTemplate, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cluster.Kube.CoreV1().
+		ConfigMaps(target.Namespace).
+		List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("list job templates: %w", err)
+	}
+
+	var out []wf.WorkflowTemplate
+	for i := range list.Items {
+		if _, ok := list.Items[i].Data[JobTemplateKey]; !ok {
+			continue
+		}
+
+		t, err := parseJobTemplate(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, *t)
+	}
+
+	return out, nil
+}
+
+func (e *JobsExecutor) GetTemplate(
+	ctx context.Context,
+	target Target,
+	name string,
+) (*wf.WorkflowTemplate, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.getTemplate(ctx, cluster, target, name)
+}
+
+func (e *JobsExecutor) ListWorkflows(
+	ctx context.Context,
+	target Target,
+	opts ListOptions,
+) (*WorkflowList, error) {
+
+	cluster, err := e.clients.Cluster(target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cluster.Kube.BatchV1().
+		Jobs(target.Namespace).
+		List(ctx, metav1.ListOptions{
+			LabelSelector: opts.LabelSelector,
+			Limit:         opts.Limit,
+			Continue:      opts.Continue,
+		})
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	out := &WorkflowList{Continue: list.Continue}
+	for i := range list.Items {
+		out.Items = append(out.Items, *toWorkflow(&list.Items[i]))
+	}
+
+	return out, nil
+}
+
+//
+// ---- Internals ----
+//
+
+func (e *JobsExecutor) submit(
+	ctx context.Context,
+	cluster *ClusterClients,
+	target Target,
+	templateName string,
+	workflow *wf.Workflow,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	mergedLabels, annotations, err := applyLabelContract(ctx, "jobs", templateName, labels)
+	if err != nil {
+		return nil, fmt.Errorf("submit job from template %s: %w", templateName, err)
+	}
+
+	job, err := toJob(workflow, mergedLabels, annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := cluster.Kube.BatchV1().
+		Jobs(target.Namespace).
+		Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create job: %w", err)
+	}
+
+	return toWorkflow(created), nil
+}
+
+func (e *JobsExecutor) getTemplate(
+	ctx context.Context,
+	cluster *ClusterClients,
+	target Target,
+	name string,
+) (*wf.WorkflowTemplate, error) {
+
+	cm, err := cluster.Kube.CoreV1().
+		ConfigMaps(target.Namespace).
+		Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get job template %s: %w", name, err)
+	}
+
+	return parseJobTemplate(cm)
+}
+
+func parseJobTemplate(cm *corev1.ConfigMap) (*wf.WorkflowTemplate, error) {
+
+	raw, ok := cm.Data[JobTemplateKey]
+	if !ok {
+		return nil, fmt.Errorf("job template %s: missing %s", cm.Name, JobTemplateKey)
+	}
+
+	var t wf.WorkflowTemplate
+	if err := yaml.Unmarshal([]byte(raw), &t); err != nil {
+		return nil, fmt.Errorf("parse job template %s: %w", cm.Name, err)
+	}
+
+	t.Name = cm.Name
+	t.Namespace = cm.Namespace
+	t.Labels = cm.Labels
+
+	return &t, nil
+}
+
+// resolveParameters combines declared defaults with submitted values
+// and checks them against the declared enums.
+//
+// Undeclared parameters are passed through: several templates
+// reference workflow parameters without declaring them.
+func resolveParameters(
+	declared []wf.Parameter,
+	submitted map[string]string,
+) (map[string]string, error) {
+
+	resolved := make(map[string]string, len(declared)+len(submitted))
+
+	for _, p := range declared {
+		switch {
+		case p.Value != nil:
+			resolved[p.Name] = p.Value.String()
+		case p.Default != nil:
+			resolved[p.Name] = p.Default.String()
+		}
+	}
+
+	for k, v := range submitted {
+		resolved[k] = v
+	}
+
+	// Argo rejects these on submission too; without the contract
+	// wrapper this is the only check a Jobs submission gets.
+	var missing, invalid []string
+	for _, p := range declared {
+		v, ok := resolved[p.Name]
+		switch {
+		case !ok:
+			missing = append(missing, p.Name)
+		case len(p.Enum) > 0 && !inEnum(p.Enum, v):
+			invalid = append(invalid, fmt.Sprintf("%s=%q (want one of %s)", p.Name, v, joinEnum(p.Enum)))
+		}
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing required parameters: "+strings.Join(missing, ", "))
+	}
+	if len(invalid) > 0 {
+		problems = append(problems, "invalid parameters: "+strings.Join(invalid, ", "))
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+
+	return resolved, nil
+}
+
+// toJob renders a single-container workflow as a Job.
+//
+// {{workflow.parameters.X}} and {{inputs.parameters.X}} are replaced
+// with argument values; the Job never retries, matching a workflow
+// step without a retry strategy.
+func toJob(
+	workflow *wf.Workflow,
+	mergedLabels map[string]string,
+	annotations map[string]string,
+) (*batchv1.Job, error) {
+
+	entry := findTemplate(workflow.Spec.Templates, workflow.Spec.Entrypoint)
+	if entry == nil {
+		return nil, fmt.Errorf("%w: entrypoint %q not found", ErrUnsupportedWorkflow, workflow.Spec.Entrypoint)
+	}
+
+	var container corev1.Container
+
+	switch {
+	case entry.Container != nil:
+		container = *entry.Container.DeepCopy()
+
+	case entry.Script != nil:
+		container = *entry.Script.Container.DeepCopy()
+		container.Args = append(container.Args, "-c", entry.Script.Source)
+
+	default:
+		return nil, fmt.Errorf(
+			"%w: entrypoint %q must be a container or script template",
+			ErrUnsupportedWorkflow,
+			entry.Name,
+		)
+	}
+
+	values := make(map[string]string, len(workflow.Spec.Arguments.Parameters))
+	for _, p := range workflow.Spec.Arguments.Parameters {
+		if p.Value != nil {
+			values[p.Name] = p.Value.String()
+		}
+	}
+
+	container.Name = "main"
+	substituteContainer(&container, parameterReplacer(values))
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("encode job parameters: %w", err)
+	}
+
+	backoffLimit := int32(0)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: workflow.GenerateName,
+			Labels:       mergedLabels,
+			Annotations: mergeAnnotations(
+				workflow.Annotations,
+				annotations,
+				map[string]string{annotationParameters: string(encoded)},
+			),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: mergedLabels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: workflow.Spec.ServiceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers:         []corev1.Container{container},
+				},
+			},
+		},
+	}, nil
+}
+
+// toWorkflow reports a Job in the Workflow shape.
+//
+//	no pods started yet        Pending
+//	pods active / started      Running
+//	condition Complete=True    Succeeded
+//	condition Failed=True      Failed (condition message)
+func toWorkflow(job *batchv1.Job) *wf.Workflow {
+
+	out := &wf.Workflow{
+		ObjectMeta: *job.ObjectMeta.DeepCopy(),
+		Spec: wf.WorkflowSpec{
+			WorkflowTemplateRef: &wf.WorkflowTemplateRef{
+				Name: job.Labels[LabelWorkflowTemplate],
+			},
+		},
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(job.Annotations[annotationParameters]), &values); err == nil {
+		for _, name := range sortedKeys(values) {
+			out.Spec.Arguments.Parameters = append(out.Spec.Arguments.Parameters, wf.Parameter{
+				Name:  name,
+				Value: wf.AnyStringPtr(values[name]),
+			})
+		}
+	}
+
+	if job.Status.StartTime != nil {
+		out.Status.StartedAt = *job.Status.StartTime
+	}
+
+	out.Status.Phase = wf.WorkflowPending
+	if job.Status.Active > 0 || job.Status.StartTime != nil {
+		out.Status.Phase = wf.WorkflowRunning
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch c.Type {
+		case batchv1.JobComplete:
+			out.Status.Phase = wf.WorkflowSucceeded
+			out.Status.FinishedAt = c.LastTransitionTime
+		case batchv1.JobFailed:
+			out.Status.Phase = wf.WorkflowFailed
+			out.Status.Message = c.Message
+			out.Status.FinishedAt = c.LastTransitionTime
+		}
+	}
+
+	if job.Status.CompletionTime != nil {
+		out.Status.FinishedAt = *job.Status.CompletionTime
+	}
+
+	return out
+}
+
+func findTemplate(templates []wf.Template, name string) *wf.Template {
+	for i := range templates {
+		if templates[i].Name == name {
+			return &templates[i]
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func substituteContainer(c *corev1.Container, r *strings.Replacer) {
+
+	c.Image = r.Replace(c.Image)
+
+	for i := range c.Command {
+		c.Command[i] = r.Replace(c.Command[i])
+	}
+	for i := range c.Args {
+		c.Args[i] = r.Replace(c.Args[i])
+	}
+	for i := range c.Env {
+		c.Env[i].Value = r.Replace(c.Env[i].Value)
+	}
+}
+
+// parameterReplacer replaces {{workflow.parameters.X}} and
+// {{inputs.parameters.X}} in a single left-to-right pass, so a value
+// that itself looks like a placeholder is never expanded, whatever
+// order the parameters come in.
+func parameterReplacer(values map[string]string) *strings.Replacer {
+
+	pairs := make([]string, 0, 4*len(values))
+	for _, name := range sortedKeys(values) {
+		pairs = append(pairs,
+			"{{workflow.parameters."+name+"}}", values[name],
+			"{{inputs.parameters."+name+"}}", values[name],
+		)
+	}
+
+	return strings.NewReplacer(pairs...)
+}
+
+func inEnum(enum []wf.AnyString, v string) bool {
+	for _, e := range enum {
+		if e.String() == v {
+			return true
+		}
+	}
+	return false
+}
+
+func joinEnum(enum []wf.AnyString) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = e.String()
+	}
+	return strings.Join(values, ", ")
+}
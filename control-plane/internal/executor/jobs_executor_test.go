@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestResolveParameters(t *testing.T) {
+
+	declared := []wf.Parameter{
+		{Name: "env_name"},
+		{Name: "size", Default: wf.AnyStringPtr("small"), Enum: []wf.AnyString{"small", "large"}},
+		{Name: "region", Value: wf.AnyStringPtr("eu")},
+	}
+
+	tests := []struct {
+		name      string
+		submitted map[string]string
+		want      map[string]string
+		wantErr   []string
+	}{
+		{
+			name:      "defaults and pass-through",
+			submitted: map[string]string{"env_name": "pr-42", "extra": "x"},
+			want:      map[string]string{"env_name": "pr-42", "size": "small", "region": "eu", "extra": "x"},
+		},
+		{
+			name:      "enum value",
+			submitted: map[string]string{"env_name": "pr-42", "size": "large"},
+			want:      map[string]string{"env_name": "pr-42", "size": "large", "region": "eu"},
+		},
+		{
+			name:      "every problem at once",
+			submitted: map[string]string{"size": "huge"},
+			wantErr: []string{
+				"missing required parameters: env_name",
+				`invalid parameters: size="huge" (want one of small, large)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveParameters(declared, tt.submitted)
+
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("resolveParameters() succeeded")
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Fatalf("resolveParameters() = %v, want %q", err, want)
+					}
+				}
+				return
+			}
+
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("resolveParameters() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteContainer(t *testing.T) {
+
+	// A value that looks like a placeholder must come through as is,
+	// whichever parameter is replaced first.
+	values := map[string]string{
+		"a": "{{workflow.parameters.b}}",
+		"b": "secret",
+		"c": "{{inputs.parameters.a}}",
+	}
+
+	for i := 0; i < 20; i++ {
+		c := corev1.Container{
+			Image:   "busybox:{{workflow.parameters.b}}",
+			Command: []string{"sh"},
+			Args:    []string{"echo {{workflow.parameters.a}} {{inputs.parameters.c}} {{workflow.parameters.unknown}}"},
+			Env:     []corev1.EnvVar{{Name: "A", Value: "{{inputs.parameters.a}}"}},
+		}
+
+		substituteContainer(&c, parameterReplacer(values))
+
+		if c.Image != "busybox:secret" {
+			t.Fatalf("image = %q", c.Image)
+		}
+		if want := "echo {{workflow.parameters.b}} {{inputs.parameters.a}} {{workflow.parameters.unknown}}"; c.Args[0] != want {
+			t.Fatalf("args = %q, want %q", c.Args[0], want)
+		}
+		if c.Env[0].Value != "{{workflow.parameters.b}}" {
+			t.Fatalf("env = %q", c.Env[0].Value)
+		}
+	}
+}
@@ -1,33 +1,173 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	argoclient "github.com/argoproj/argo-workflows/v3/pkg/client/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-type Clients struct {
+var ErrUnknownCluster = errors.New("unknown cluster")
+
+// ClusterSource describes how to reach one named cluster.
+//
+// Exactly one of the following is used:
+//   - Kubeconfig: a kubeconfig file, typically mounted from a Secret
+//   - Context:    a context in the default kubeconfig
+//
+// Context may be combined with Kubeconfig to pick a non-current context.
+type ClusterSource struct {
+	Name       string
+	Kubeconfig string
+	Context    string
+}
+
+// ClusterClients holds the typed clients for one cluster.
+type ClusterClients struct {
+	Name string
 	Argo argoclient.Interface
+	Kube kubernetes.Interface
+}
+
+// Clients holds a connection per named cluster.
+//
+// The default cluster is the one the control plane runs in
+// (or the current kubeconfig context in local development).
+type Clients struct {
+	defaultCluster string
+	clusters       map[string]*ClusterClients
 }
 
-func NewClients() (*Clients, error) {
+// NewClients connects to the default cluster under defaultCluster
+// and to every additional source.
+func NewClients(
+	defaultCluster string,
+	sources []ClusterSource,
+) (*Clients, error) {
 
 	cfg, err := buildConfig()
 	if err != nil {
 		return nil, fmt.Errorf("build kube config: %w", err)
 	}
 
+	defaultClients, err := newClusterClients(defaultCluster, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := &Clients{
+		defaultCluster: defaultCluster,
+		clusters: map[string]*ClusterClients{
+			defaultCluster: defaultClients,
+		},
+	}
+
+	for _, src := range sources {
+
+		if _, exists := clients.clusters[src.Name]; exists {
+			return nil, fmt.Errorf("cluster %s configured twice", src.Name)
+		}
+
+		cfg, err := buildSourceConfig(src)
+		if err != nil {
+			return nil, fmt.Errorf("build kube config for cluster %s: %w", src.Name, err)
+		}
+
+		cc, err := newClusterClients(src.Name, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		clients.clusters[src.Name] = cc
+	}
+
+	return clients, nil
+}
+
+// Cluster returns the clients for a named cluster.
+// An empty name selects the default cluster.
+func (c *Clients) Cluster(name string) (*ClusterClients, error) {
+
+	if name == "" {
+		name = c.defaultCluster
+	}
+
+	cc, ok := c.clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCluster, name)
+	}
+
+	return cc, nil
+}
+
+// DefaultCluster returns the name of the default cluster.
+func (c *Clients) DefaultCluster() string {
+	return c.defaultCluster
+}
+
+// ClusterNames returns every configured cluster, sorted.
+func (c *Clients) ClusterNames() []string {
+
+	names := make([]string, 0, len(c.clusters))
+	for name := range c.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// DiscoverClusterSources lists kubeconfig files in dir, one cluster
+// per file, named after the file.
+//
+// This matches a Secret mounted as a volume with one key per cluster.
+// Hidden files (Kubernetes' ..data symlinks) are skipped.
+func DiscoverClusterSources(dir string) ([]ClusterSource, error) {
+
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read cluster config dir: %w", err)
+	}
+
+	var sources []ClusterSource
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		sources = append(sources, ClusterSource{
+			Name:       e.Name(),
+			Kubeconfig: filepath.Join(dir, e.Name()),
+		})
+	}
+
+	return sources, nil
+}
+
+func newClusterClients(name string, cfg *rest.Config) (*ClusterClients, error) {
+
 	argo, err := argoclient.NewForConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("create argo client: %w", err)
+		return nil, fmt.Errorf("create argo client for cluster %s: %w", name, err)
 	}
 
-	return &Clients{
-		Argo: argo,
-	}, nil
+	kube, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create kubernetes client for cluster %s: %w", name, err)
+	}
+
+	return &ClusterClients{Name: name, Argo: argo, Kube: kube}, nil
 }
 
 func buildConfig() (*rest.Config, error) {
@@ -45,3 +185,16 @@ func buildConfig() (*rest.Config, error) {
 
 	return clientcmd.BuildConfigFromFlags("", kubeconfig)
 }
+
+func buildSourceConfig(src ClusterSource) (*rest.Config, error) {
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if src.Kubeconfig != "" {
+		rules.ExplicitPath = src.Kubeconfig
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules,
+		&clientcmd.ConfigOverrides{CurrentContext: src.Context},
+	).ClientConfig()
+}
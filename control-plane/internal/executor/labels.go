@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//
+// Label Contract
+//
+// Every workflow the control plane submits carries these labels.
+// Searching, reconciliation and cleanup all select on them, so the
+// executor — not its callers — is the last line of defence.
+//
+//	reserved   set by the executor only; callers may not supply them
+//	required   must be supplied by the caller, non-empty
+//
+// Any other platform.* or free-form label is passed through.
+//
+
+const (
+	LabelControlPlane     = "platform.control-plane"
+	LabelExecutor         = "platform.executor"
+	LabelWorkflowTemplate = "platform.workflow.template"
+	LabelWorkflowType     = "platform.workflow.type"
+	LabelService          = "platform.service"
+)
+
+// AnnotationOriginalLabelPrefix prefixes the annotation holding the
+// caller's value of a label that had to be rewritten, e.g.
+//
+//	platform.labels/platform.service: "Payments API (EU)"
+const AnnotationOriginalLabelPrefix = "platform.labels/"
+
+// ErrLabelContract is returned when a submission violates the contract.
+var ErrLabelContract = errors.New("label contract violation")
+
+var reservedLabels = []string{
+	LabelControlPlane,
+	LabelExecutor,
+	LabelWorkflowTemplate,
+}
+
+var requiredLabels = []string{
+	LabelWorkflowType,
+	LabelService,
+}
+
+// maxLabelValue is the Kubernetes limit on label value length.
+const maxLabelValue = 63
+
+// labelHashLength is the number of hex characters of the SHA-256 of
+// the original value appended to truncated values.
+const labelHashLength = 10
+
+// applyLabelContract validates caller labels and returns the labels
+// and annotations to set on the submitted object, trace annotations
+// for ctx included.
+//
+// All violations are reported together.
+func applyLabelContract(
+	ctx context.Context,
+	executorName string,
+	templateName string,
+	labels map[string]string,
+) (map[string]string, map[string]string, error) {
+
+	var problems []string
+
+	for _, k := range reservedLabels {
+		if _, ok := labels[k]; ok {
+			problems = append(problems, fmt.Sprintf("%s is reserved for the executor", k))
+		}
+	}
+
+	for _, k := range requiredLabels {
+		if LabelValue(labels[k]) == "" {
+			problems = append(problems, fmt.Sprintf("%s is required", k))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, nil, fmt.Errorf("%w: %s", ErrLabelContract, strings.Join(problems, "; "))
+	}
+
+	merged := make(map[string]string, len(labels)+len(reservedLabels))
+	annotations := make(map[string]string)
+
+	for k, v := range TraceAnnotations(ctx) {
+		annotations[k] = v
+	}
+
+	set := func(k, v string) {
+		value := LabelValue(v)
+		if value != v {
+			annotations[AnnotationOriginalLabelPrefix+k] = v
+		}
+		merged[k] = value
+	}
+
+	for k, v := range labels {
+		set(k, v)
+	}
+
+	set(LabelControlPlane, "true")
+	set(LabelExecutor, executorName)
+	set(LabelWorkflowTemplate, templateName)
+
+	return merged, annotations, nil
+}
+
+// LabelValue converts an arbitrary string into a valid label value.
+//
+// Characters outside [A-Za-z0-9._-] become '-', and the value is
+// trimmed to start and end alphanumerically. Values still longer than
+// 63 characters are truncated and suffixed with a hash of the original,
+// so distinct inputs stay distinct and the mapping is deterministic.
+//
+// Callers selecting on labels must apply the same conversion.
+func LabelValue(v string) string {
+
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, v)
+
+	sanitized = strings.TrimFunc(sanitized, isLabelPunctuation)
+
+	if sanitized == "" && v != "" {
+		return labelHash(v)
+	}
+
+	if len(sanitized) <= maxLabelValue {
+		return sanitized
+	}
+
+	prefix := strings.TrimRightFunc(
+		sanitized[:maxLabelValue-labelHashLength-1],
+		isLabelPunctuation,
+	)
+
+	return prefix + "-" + labelHash(v)
+}
+
+func labelHash(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:])[:labelHashLength]
+}
+
+func isLabelPunctuation(r rune) bool {
+	return r == '-' || r == '_' || r == '.'
+}
+
+func mergeAnnotations(maps ...map[string]string) map[string]string {
+
+	out := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	return out
+}
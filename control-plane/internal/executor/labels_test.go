@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestLabelValue(t *testing.T) {
+
+	long := strings.Repeat("a", 80)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"valid", "payments-api_v1.2", "payments-api_v1.2"},
+		{"spaces and parentheses", "Payments API (EU)", "Payments-API--EU"},
+		{"leading and trailing punctuation", "--x.y_", "x.y"},
+		{"only punctuation", "!!!", labelHash("!!!")},
+		{"exactly 63", long[:63], long[:63]},
+		{"too long", long, long[:52] + "-" + labelHash(long)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LabelValue(tt.in)
+			if got != tt.want {
+				t.Fatalf("LabelValue(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if errs := validation.IsValidLabelValue(got); len(errs) > 0 {
+				t.Fatalf("LabelValue(%q) = %q is not a label value: %v", tt.in, got, errs)
+			}
+		})
+	}
+}
+
+func TestLabelValueKeepsLongValuesDistinct(t *testing.T) {
+
+	a := strings.Repeat("x", 70) + "a"
+	b := strings.Repeat("x", 70) + "b"
+
+	if LabelValue(a) == LabelValue(b) {
+		t.Fatalf("distinct values map to the same label %q", LabelValue(a))
+	}
+}
+
+func TestApplyLabelContract(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			labels: map[string]string{
+				LabelWorkflowType: "ci",
+				LabelService:      "payments",
+			},
+		},
+		{
+			name: "missing required",
+			labels: map[string]string{
+				LabelWorkflowType: "ci",
+			},
+			wantErr: LabelService + " is required",
+		},
+		{
+			name: "reserved",
+			labels: map[string]string{
+				LabelWorkflowType: "ci",
+				LabelService:      "payments",
+				LabelExecutor:     "mine",
+			},
+			wantErr: LabelExecutor + " is reserved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, _, err := applyLabelContract(context.Background(), "argo", "ci-template", tt.labels)
+
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrLabelContract) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if labels[LabelControlPlane] != "true" || labels[LabelExecutor] != "argo" {
+				t.Fatalf("reserved labels not set: %v", labels)
+			}
+		})
+	}
+}
+
+func TestApplyLabelContractRecordsOriginals(t *testing.T) {
+
+	labels, annotations, err := applyLabelContract(context.Background(), "argo", "ci-template", map[string]string{
+		LabelWorkflowType: "ci",
+		LabelService:      "Payments API",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := labels[LabelService]; got != "Payments-API" {
+		t.Fatalf("label = %q, want %q", got, "Payments-API")
+	}
+	if got := annotations[AnnotationOriginalLabelPrefix+LabelService]; got != "Payments API" {
+		t.Fatalf("original annotation = %q, want %q", got, "Payments API")
+	}
+}
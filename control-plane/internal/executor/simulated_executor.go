@@ -0,0 +1,520 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+var _ WorkflowExecutor = (*SimulatedExecutor)(nil)
+var _ TemplateReader = (*SimulatedExecutor)(nil)
+var _ WorkflowLister = (*SimulatedExecutor)(nil)
+
+// SimulatedExecutor is an in-memory stand-in for Argo.
+//
+// It exists so the control plane can run without Kubernetes:
+// on a laptop, in CI for API clients, and in unit tests.
+//
+// Workflows move through Pending -> Running -> Succeeded|Failed
+// purely as a function of time since submission, following the
+// first matching SimulationRule. Nothing is executed.
+type SimulatedExecutor struct {
+	defaultCluster string
+	simulation     Simulation
+
+	mu        sync.RWMutex
+	workflows map[string]*simulatedWorkflow
+	templates []wf.WorkflowTemplate
+}
+
+// Simulation is the timing script for simulated workflows.
+//
+// Example (YAML):
+//
+//	default:
+//	  pending: 2s
+//	  running: 10s
+//	rules:
+//	  - template: env-create-template
+//	    labels:
+//	      platform.service: flaky-service
+//	    running: 30s
+//	    fail: true
+//	    message: simulated provisioning failure
+type Simulation struct {
+	Default SimulationTiming `json:"default"`
+	Rules   []SimulationRule `json:"rules"`
+}
+
+// SimulationTiming controls how long a workflow stays in each phase
+// and how it ends.
+type SimulationTiming struct {
+	Pending Duration `json:"pending"`
+	Running Duration `json:"running"`
+	Fail    bool     `json:"fail"`
+	Message string   `json:"message"`
+}
+
+// SimulationRule overrides the default timing for workflows submitted
+// from Template (empty matches any) carrying all of Labels.
+type SimulationRule struct {
+	Template string            `json:"template"`
+	Labels   map[string]string `json:"labels"`
+
+	SimulationTiming
+}
+
+// Duration is a time.Duration that reads from "10s"-style strings.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	parsed, err := time.ParseDuration(strings.Trim(string(b), `"`))
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// DefaultSimulation succeeds every workflow after a short delay.
+var DefaultSimulation = Simulation{
+	Default: SimulationTiming{
+		Pending: Duration{2 * time.Second},
+		Running: Duration{8 * time.Second},
+	},
+}
+
+type simulatedWorkflow struct {
+	workflow  *wf.Workflow
+	timing    SimulationTiming
+	cancelled *time.Time
+}
+
+// NewSimulatedExecutor creates an empty simulation. Targets without a
+// cluster resolve to defaultCluster, as they do against real clusters.
+func NewSimulatedExecutor(
+	defaultCluster string,
+	simulation Simulation,
+	templates []wf.WorkflowTemplate,
+) *SimulatedExecutor {
+	return &SimulatedExecutor{
+		defaultCluster: defaultCluster,
+		simulation:     simulation,
+		workflows:      make(map[string]*simulatedWorkflow),
+		templates:      templates,
+	}
+}
+
+// LoadSimulation reads a timing script from a YAML file.
+// An empty path yields DefaultSimulation.
+func LoadSimulation(path string) (Simulation, error) {
+
+	if path == "" {
+		return DefaultSimulation, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Simulation{}, fmt.Errorf("read simulation script: %w", err)
+	}
+
+	var sim Simulation
+	if err := yaml.UnmarshalStrict(data, &sim); err != nil {
+		return Simulation{}, fmt.Errorf("parse simulation script %s: %w", path, err)
+	}
+
+	return sim, nil
+}
+
+// LoadTemplates reads every WorkflowTemplate YAML file in dirs.
+//
+// Used to seed the simulated executor with the repository's own
+// templates (argo/workflowtemplates, workflows/templates). Relative
+// dirs are resolved against the working directory; a dir that does not
+// exist is an error rather than an empty catalog.
+func LoadTemplates(dirs ...string) ([]wf.WorkflowTemplate, error) {
+
+	var out []wf.WorkflowTemplate
+
+	for _, dir := range dirs {
+		if err := checkDir(dir); err != nil {
+			return nil, err
+		}
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("read template %s: %w", f, err)
+			}
+
+			var t wf.WorkflowTemplate
+			if err := yaml.Unmarshal(data, &t); err != nil {
+				return nil, fmt.Errorf("parse template %s: %w", f, err)
+			}
+
+			if t.Kind != "WorkflowTemplate" {
+				continue
+			}
+
+			out = append(out, t)
+		}
+	}
+
+	return out, nil
+}
+
+// checkDir fails unless dir is an existing directory, naming the path
+// it resolved to.
+func checkDir(dir string) error {
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("template dir %s (%s): %w", dir, abs, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("template dir %s (%s): not a directory", dir, abs)
+	}
+
+	return nil
+}
+
+func (e *SimulatedExecutor) SubmitFromTemplate(
+	ctx context.Context,
+	target Target,
+	templateName string,
+	generateName string,
+	parameters map[string]string,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	if !e.hasTemplate(templateName) {
+		return nil, fmt.Errorf(
+			"submit workflow from template %s: %w",
+			templateName,
+			apierrors.NewNotFound(wf.Resource("workflowtemplates"), templateName),
+		)
+	}
+
+	parameters = withTraceParameter(ctx, parameters)
+
+	args := wf.Arguments{}
+	for k, v := range parameters {
+		args.Parameters = append(args.Parameters, wf.Parameter{
+			Name:  k,
+			Value: wf.AnyStringPtr(v),
+		})
+	}
+
+	workflow := &wf.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+		},
+		Spec: wf.WorkflowSpec{
+			WorkflowTemplateRef: &wf.WorkflowTemplateRef{
+				Name: templateName,
+			},
+			Arguments: args,
+		},
+	}
+
+	mergedLabels, annotations, err := applyLabelContract(ctx, "fake", templateName, labels)
+	if err != nil {
+		return nil, fmt.Errorf("submit workflow from template %s: %w", templateName, err)
+	}
+	workflow.Annotations = mergeAnnotations(annotations)
+
+	return e.create(target, workflow, mergedLabels)
+}
+
+func (e *SimulatedExecutor) SubmitWorkflow(
+	ctx context.Context,
+	target Target,
+	workflow *wf.Workflow,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+	mergedLabels, annotations, err := applyLabelContract(ctx, "fake", GeneratedTemplate, labels)
+	if err != nil {
+		return nil, fmt.Errorf("submit generated workflow %s: %w", workflow.GenerateName, err)
+	}
+
+	submitted := workflow.DeepCopy()
+	addTraceArgument(ctx, submitted)
+	submitted.Annotations = mergeAnnotations(submitted.Annotations, annotations)
+
+	return e.create(target, submitted, mergedLabels)
+}
+
+func (e *SimulatedExecutor) GetWorkflow(
+	ctx context.Context,
+	target Target,
+	name string,
+) (*wf.Workflow, error) {
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	sw, ok := e.workflows[e.key(target, name)]
+	if !ok {
+		return nil, fmt.Errorf(
+			"get workflow %s: %w",
+			name,
+			apierrors.NewNotFound(wf.Resource("workflows"), name),
+		)
+	}
+
+	return sw.observe(time.Now()), nil
+}
+
+func (e *SimulatedExecutor) Cancel(
+	ctx context.Context,
+	target Target,
+	name string,
+) error {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sw, ok := e.workflows[e.key(target, name)]
+	if !ok {
+		return fmt.Errorf(
+			"cancel workflow %s: %w",
+			name,
+			apierrors.NewNotFound(wf.Resource("workflows"), name),
+		)
+	}
+
+	if sw.cancelled == nil && !sw.observe(time.Now()).Status.Phase.Completed() {
+		now := time.Now()
+		sw.cancelled = &now
+	}
+
+	return nil
+}
+
+func (e *SimulatedExecutor) ListTemplates(
+	ctx context.Context,
+	target Target,
+	labelSelector string,
+) ([]wf.WorkflowTemplate, error) {
+
+	selector, err := k8slabels.Parse(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow templates: %w", err)
+	}
+
+	var out []wf.WorkflowTemplate
+	for _, t := range e.templates {
+		if selector.Matches(k8slabels.Set(t.Labels)) {
+			copied := *t.DeepCopy()
+			copied.Namespace = target.Namespace
+			out = append(out, copied)
+		}
+	}
+
+	return out, nil
+}
+
+func (e *SimulatedExecutor) GetTemplate(
+	ctx context.Context,
+	target Target,
+	name string,
+) (*wf.WorkflowTemplate, error) {
+
+	for _, t := range e.templates {
+		if t.Name == name {
+			copied := t.DeepCopy()
+			copied.Namespace = target.Namespace
+			return copied, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"get workflow template %s: %w",
+		name,
+		apierrors.NewNotFound(wf.Resource("workflowtemplates"), name),
+	)
+}
+
+// ListWorkflows pages through simulated workflows in name order.
+// The continue token is the last name returned.
+func (e *SimulatedExecutor) ListWorkflows(
+	ctx context.Context,
+	target Target,
+	opts ListOptions,
+) (*WorkflowList, error) {
+
+	selector, err := k8slabels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("list workflows: %w", err)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	prefix := e.key(target, "")
+
+	var names []string
+	for k, sw := range e.workflows {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if !selector.Matches(k8slabels.Set(sw.workflow.Labels)) {
+			continue
+		}
+		if name := sw.workflow.Name; name > opts.Continue {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := &WorkflowList{}
+	now := time.Now()
+
+	for _, name := range names {
+		if opts.Limit > 0 && int64(len(out.Items)) == opts.Limit {
+			out.Continue = out.Items[len(out.Items)-1].Name
+			break
+		}
+		out.Items = append(out.Items, *e.workflows[e.key(target, name)].observe(now))
+	}
+
+	return out, nil
+}
+
+//
+// ---- Internals ----
+//
+
+func (e *SimulatedExecutor) create(
+	target Target,
+	workflow *wf.Workflow,
+	mergedLabels map[string]string,
+) (*wf.Workflow, error) {
+
+	workflow.Name = workflow.GenerateName + strings.ToLower(uuid.NewString()[:5])
+	workflow.Namespace = target.Namespace
+	workflow.Labels = mergedLabels
+	workflow.UID = types.UID(uuid.NewString())
+	workflow.CreationTimestamp = metav1.Now()
+
+	sw := &simulatedWorkflow{
+		workflow: workflow,
+		timing:   e.timingFor(mergedLabels),
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.workflows[e.key(target, workflow.Name)] = sw
+
+	return sw.observe(workflow.CreationTimestamp.Time), nil
+}
+
+func (e *SimulatedExecutor) hasTemplate(name string) bool {
+	for _, t := range e.templates {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// timingFor returns the first rule matching the workflow's template
+// and labels, or the default timing.
+func (e *SimulatedExecutor) timingFor(workflowLabels map[string]string) SimulationTiming {
+
+	template := workflowLabels[LabelWorkflowTemplate]
+
+	for _, r := range e.simulation.Rules {
+		if r.Template != "" && r.Template != template {
+			continue
+		}
+
+		if !k8slabels.SelectorFromSet(r.Labels).Matches(k8slabels.Set(workflowLabels)) {
+			continue
+		}
+
+		return r.SimulationTiming
+	}
+
+	return e.simulation.Default
+}
+
+// observe derives the workflow's state at now from its timing script.
+func (sw *simulatedWorkflow) observe(now time.Time) *wf.Workflow {
+
+	out := sw.workflow.DeepCopy()
+
+	created := sw.workflow.CreationTimestamp.Time
+	startedAt := created.Add(sw.timing.Pending.Duration)
+	finishedAt := startedAt.Add(sw.timing.Running.Duration)
+
+	if sw.cancelled != nil && sw.cancelled.Before(finishedAt) {
+		finishedAt = *sw.cancelled
+		if finishedAt.Before(startedAt) {
+			startedAt = finishedAt
+		}
+	}
+
+	switch {
+	case now.Before(startedAt):
+		out.Status.Phase = wf.WorkflowPending
+
+	case now.Before(finishedAt):
+		out.Status.Phase = wf.WorkflowRunning
+		out.Status.StartedAt = metav1.NewTime(startedAt)
+
+	default:
+		out.Status.StartedAt = metav1.NewTime(startedAt)
+		out.Status.FinishedAt = metav1.NewTime(finishedAt)
+
+		switch {
+		case sw.cancelled != nil && !sw.cancelled.After(finishedAt):
+			out.Status.Phase = wf.WorkflowFailed
+			out.Status.Message = "Stopped with strategy 'Terminate'"
+		case sw.timing.Fail:
+			out.Status.Phase = wf.WorkflowFailed
+			out.Status.Message = sw.timing.Message
+			if out.Status.Message == "" {
+				out.Status.Message = "simulated failure"
+			}
+		default:
+			out.Status.Phase = wf.WorkflowSucceeded
+		}
+	}
+
+	return out
+}
+
+func (e *SimulatedExecutor) key(target Target, name string) string {
+	cluster := target.Cluster
+	if cluster == "" {
+		cluster = e.defaultCluster
+	}
+	return cluster + "/" + target.Namespace + "/" + name
+}
@@ -0,0 +1,9 @@
+package executor
+
+// Target identifies where a workflow executes.
+//
+// An empty Cluster selects the default cluster.
+type Target struct {
+	Cluster   string
+	Namespace string
+}
@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"context"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+)
+
+// TemplateReader exposes read-only access to the WorkflowTemplates
+// installed in the execution plane.
+//
+// It is deliberately separate from WorkflowExecutor:
+// discovering what CAN run is not the same as submitting intent.
+type TemplateReader interface {
+
+	// ListTemplates returns every WorkflowTemplate in the target
+	// namespace matching the given Kubernetes label selector.
+	ListTemplates(
+		ctx context.Context,
+		target Target,
+		labelSelector string,
+	) ([]wf.WorkflowTemplate, error)
+
+	// GetTemplate returns one WorkflowTemplate by name, read live
+	// from the target rather than from any cache.
+	GetTemplate(
+		ctx context.Context,
+		target Target,
+		name string,
+	) (*wf.WorkflowTemplate, error)
+}
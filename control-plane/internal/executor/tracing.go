@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"context"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace propagation into workflows.
+//
+// A workflow submitted while serving a traced request carries the
+// trace ID and W3C traceparent as annotations, and the trace ID as the
+// trace_id workflow parameter, so steps can log it or continue the
+// trace.
+const (
+	AnnotationTraceID     = "platform.trace-id"
+	AnnotationTraceparent = "platform.traceparent"
+	ParameterTraceID      = "trace_id"
+)
+
+// TraceAnnotations returns the trace annotations for ctx, or nil when
+// ctx carries no valid span context.
+func TraceAnnotations(ctx context.Context) map[string]string {
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	flags := "00"
+	if sc.IsSampled() {
+		flags = "01"
+	}
+
+	return map[string]string{
+		AnnotationTraceID:     sc.TraceID().String(),
+		AnnotationTraceparent: "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-" + flags,
+	}
+}
+
+// withTraceParameter returns parameters plus trace_id, without
+// modifying the caller's map. A caller-supplied trace_id wins.
+func withTraceParameter(ctx context.Context, parameters map[string]string) map[string]string {
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return parameters
+	}
+	if _, ok := parameters[ParameterTraceID]; ok {
+		return parameters
+	}
+
+	out := make(map[string]string, len(parameters)+1)
+	for k, v := range parameters {
+		out[k] = v
+	}
+	out[ParameterTraceID] = sc.TraceID().String()
+
+	return out
+}
+
+// addTraceArgument appends the trace_id argument to a generated
+// workflow unless it declares one already.
+func addTraceArgument(ctx context.Context, workflow *wf.Workflow) {
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || workflow.Spec.Arguments.GetParameterByName(ParameterTraceID) != nil {
+		return
+	}
+
+	workflow.Spec.Arguments.Parameters = append(workflow.Spec.Arguments.Parameters, wf.Parameter{
+		Name:  ParameterTraceID,
+		Value: wf.AnyStringPtr(sc.TraceID().String()),
+	})
+}
@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"context"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+)
+
+// WorkflowLister exposes read-only listing of submitted workflows.
+//
+// Like TemplateReader it is kept apart from WorkflowExecutor:
+// searching history is not submitting intent.
+type WorkflowLister interface {
+
+	// ListWorkflows returns one page of workflows in the target
+	// namespace matching opts.LabelSelector.
+	ListWorkflows(
+		ctx context.Context,
+		target Target,
+		opts ListOptions,
+	) (*WorkflowList, error)
+}
+
+// ListOptions selects and pages workflows.
+//
+// Continue is the opaque token returned with the previous page.
+type ListOptions struct {
+	LabelSelector string
+	Limit         int64
+	Continue      string
+}
+
+// WorkflowList is one page of workflows.
+// An empty Continue means there are no further pages.
+type WorkflowList struct {
+	Items    []wf.Workflow
+	Continue string
+}
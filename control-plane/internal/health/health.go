@@ -0,0 +1,178 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Check probes one dependency of the control plane. A nil error means
+// the dependency is usable.
+type Check interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// CheckFunc adapts a function to Check.
+type CheckFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func NewCheck(name string, fn func(ctx context.Context) error) CheckFunc {
+	return CheckFunc{name: name, fn: fn}
+}
+
+func (c CheckFunc) Name() string                    { return c.name }
+func (c CheckFunc) Check(ctx context.Context) error { return c.fn(ctx) }
+
+// Statuses of a single check and of the whole report.
+const (
+	StatusOK       = "ok"
+	StatusFailed   = "failed"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+	StatusDegraded = "degraded"
+)
+
+// Result is the outcome of one check.
+//
+// Critical checks make the control plane not ready when they fail;
+// others only mark it degraded, since routing traffic away from the
+// pod would not help (e.g. the repository provider being down).
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of every check.
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Ready reports whether every critical check passed.
+func (r Report) Ready() bool {
+	return r.Status != StatusNotReady
+}
+
+// Checker runs registered checks concurrently, each bounded by a
+// timeout, so one hanging dependency cannot stall the probe.
+//
+// Status changes of a check are logged once, not on every probe.
+type Checker struct {
+	timeout time.Duration
+	logger  *zap.Logger
+
+	mu     sync.Mutex
+	checks []registered
+	last   map[string]string
+}
+
+type registered struct {
+	check    Check
+	critical bool
+}
+
+func NewChecker(timeout time.Duration, logger *zap.Logger) *Checker {
+	return &Checker{
+		timeout: timeout,
+		logger:  logger,
+		last:    make(map[string]string),
+	}
+}
+
+// Register adds a check. Checks are reported in registration order.
+func (c *Checker) Register(check Check, critical bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, registered{check: check, critical: critical})
+}
+
+// Run executes every check and summarises them.
+func (c *Checker) Run(ctx context.Context) Report {
+
+	c.mu.Lock()
+	checks := append([]registered(nil), c.checks...)
+	c.mu.Unlock()
+
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for i, reg := range checks {
+		wg.Add(1)
+		go func(i int, reg registered) {
+			defer wg.Done()
+			results[i] = c.run(ctx, reg)
+		}(i, reg)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, Checks: results}
+
+	for _, res := range results {
+		switch {
+		case res.Status == StatusOK:
+		case res.Critical:
+			report.Status = StatusNotReady
+		case report.Status == StatusReady:
+			report.Status = StatusDegraded
+		}
+	}
+
+	c.logChanges(results)
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, reg registered) Result {
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := reg.check.Check(ctx)
+
+	res := Result{
+		Name:      reg.check.Name(),
+		Status:    StatusOK,
+		Critical:  reg.critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		res.Status = StatusFailed
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+func (c *Checker) logChanges(results []Result) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, res := range results {
+		previous, seen := c.last[res.Name]
+		c.last[res.Name] = res.Status
+
+		switch {
+		case previous == res.Status:
+		case res.Status == StatusFailed:
+			c.logger.Warn("health check failing",
+				zap.String("check", res.Name),
+				zap.Bool("critical", res.Critical),
+				zap.String("error", res.Error),
+			)
+		case seen:
+			c.logger.Info("health check recovered", zap.String("check", res.Name))
+		}
+	}
+}
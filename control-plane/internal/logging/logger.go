@@ -4,7 +4,9 @@ import (
 	"go.uber.org/zap"
 )
 
-func New(level string) (*zap.Logger, error) {
+// New builds the process logger. The returned level can be changed at
+// runtime, e.g. through the admin log-level endpoint.
+func New(level string) (*zap.Logger, zap.AtomicLevel, error) {
 	cfg := zap.NewProductionConfig()
 
 	switch level {
@@ -18,5 +20,10 @@ func New(level string) (*zap.Logger, error) {
 		cfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
 	}
 
-	return cfg.Build()
+	logger, err := cfg.Build()
+	if err != nil {
+		return nil, zap.AtomicLevel{}, err
+	}
+
+	return logger, cfg.Level, nil
 }
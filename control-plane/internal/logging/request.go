@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs.
+const maxRequestIDLength = 128
+
+// RouteFunc returns the route pattern serving a request, or "" when
+// none matches. *http.ServeMux's Handler method has this shape.
+type RouteFunc func(r *http.Request) (http.Handler, string)
+
+// requestLog is the request-scoped logger. Layers that learn more
+// about the request (principal, service, environment) add fields to
+// it, and the access line carries all of them.
+type requestLog struct {
+	mu        sync.Mutex
+	logger    *zap.Logger
+	requestID string
+}
+
+type requestLogKey struct{}
+
+// FromContext returns the request-scoped logger, or fallback outside
+// a request.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+
+	rl, ok := ctx.Value(requestLogKey{}).(*requestLog)
+	if !ok {
+		return fallback
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return rl.logger
+}
+
+// AddFields adds fields to the request-scoped logger for the rest of
+// the request. It is a no-op outside a request.
+func AddFields(ctx context.Context, fields ...zap.Field) {
+
+	rl, ok := ctx.Value(requestLogKey{}).(*requestLog)
+	if !ok {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.logger = rl.logger.With(fields...)
+}
+
+// RequestID returns the request's ID, or "" outside a request.
+func RequestID(ctx context.Context) string {
+	if rl, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		return rl.requestID
+	}
+	return ""
+}
+
+// Middleware assigns each request an ID (propagating a well-formed
+// X-Request-ID from the client), stores a child logger carrying it in
+// the context, and logs one access line per request.
+//
+// It runs inside tracing, so the trace ID is logged too, and outside
+// authentication, so rejected requests are logged.
+func Middleware(base *zap.Logger, route RouteFunc) func(http.Handler) http.Handler {
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			start := time.Now()
+
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			fields := []zap.Field{
+				zap.String("request_id", id),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+			}
+			if _, pattern := route(r); pattern != "" {
+				fields = append(fields, zap.String("route", pattern))
+			}
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				fields = append(fields, zap.String("trace_id", sc.TraceID().String()))
+			}
+
+			rl := &requestLog{
+				logger:    base.With(fields...),
+				requestID: id,
+			}
+
+			rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), requestLogKey{}, rl)))
+
+			rl.mu.Lock()
+			logger := rl.logger
+			rl.mu.Unlock()
+
+			logger.Info("http request",
+				zap.Int("status", rw.status),
+				zap.Int64("bytes", rw.bytes),
+				zap.Duration("latency", time.Since(start)),
+				zap.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// validRequestID accepts short IDs of printable ASCII, so that clients
+// cannot inject arbitrary content into logs.
+func validRequestID(id string) bool {
+
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,152 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/providers"
+)
+
+// ErrUnverified is returned when a trigger's branch, revision or pull
+// request does not match the repository.
+var ErrUnverified = errors.New("trigger does not match the repository")
+
+// Event is what started a run or environment, as claimed by the
+// caller until Resolve has checked it.
+//
+// For pull_request events, Branch is the pull request's base branch.
+type Event struct {
+	Name        string
+	Branch      string
+	Revision    string
+	PullRequest int
+}
+
+// Loader fetches manifests from service repositories.
+type Loader struct {
+	provider providers.RepositoryProvider
+}
+
+func NewLoader(provider providers.RepositoryProvider) *Loader {
+	return &Loader{
+		provider: provider,
+	}
+}
+
+// Load returns the manifest at revision.
+//
+// A repository without a manifest is not an error: Load returns
+// (nil, nil) and callers fall back to platform defaults. So is a
+// repository on a host the provider does not serve.
+func (l *Loader) Load(
+	ctx context.Context,
+	repoURL string,
+	revision string,
+) (*Manifest, error) {
+
+	if repoURL == "" {
+		return nil, nil
+	}
+
+	data, err := l.provider.FetchFile(ctx, repoURL, revision, FileName)
+	switch {
+	case errors.Is(err, providers.ErrFileNotFound), errors.Is(err, providers.ErrUnsupportedRepository):
+		return nil, nil
+	case errors.Is(err, providers.ErrFileTooLarge):
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	case err != nil:
+		return nil, fmt.Errorf("fetch %s: %w", FileName, err)
+	}
+
+	return Parse(data)
+}
+
+// Resolve checks ev against the repository, then loads the manifest at
+// the resolved revision, so that trigger rules are applied to what the
+// repository says rather than to what the caller claims:
+//
+//   - a pull_request event must name an open pull request; its base
+//     branch is the event's branch and its head the revision
+//   - a branch without a revision resolves to the branch itself
+//   - a branch with a revision must contain the revision
+//
+// Mismatches are ErrUnverified. Services without a repository, or
+// with one on a host the provider does not serve, have no manifest, so
+// there is nothing to check.
+func (l *Loader) Resolve(
+	ctx context.Context,
+	repoURL string,
+	ev Event,
+) (Event, *Manifest, error) {
+
+	ev, err := l.verify(ctx, repoURL, ev)
+	if errors.Is(err, providers.ErrUnsupportedRepository) {
+		return ev, nil, nil
+	}
+	if err != nil {
+		return ev, nil, err
+	}
+
+	m, err := l.Load(ctx, repoURL, ev.Revision)
+
+	return ev, m, err
+}
+
+func (l *Loader) verify(
+	ctx context.Context,
+	repoURL string,
+	ev Event,
+) (Event, error) {
+
+	if ev.Name == EventPullRequest {
+		if ev.PullRequest <= 0 {
+			return ev, fmt.Errorf("%w: pull_request events need a pull request number", ErrUnverified)
+		}
+		if repoURL == "" {
+			return ev, nil
+		}
+
+		pr, err := l.provider.PullRequest(ctx, repoURL, ev.PullRequest)
+		switch {
+		case errors.Is(err, providers.ErrPullRequestNotFound):
+			return ev, fmt.Errorf("%w: no pull request %d", ErrUnverified, ev.PullRequest)
+		case err != nil:
+			return ev, err
+		case !pr.Open:
+			return ev, fmt.Errorf("%w: pull request %d is not open", ErrUnverified, ev.PullRequest)
+		case ev.Branch != "" && ev.Branch != pr.Base:
+			return ev, fmt.Errorf("%w: pull request %d targets %s, not %s", ErrUnverified, ev.PullRequest, pr.Base, ev.Branch)
+		case ev.Revision != "" && ev.Revision != pr.HeadSHA:
+			return ev, fmt.Errorf("%w: revision %s is not the head of pull request %d", ErrUnverified, ev.Revision, ev.PullRequest)
+		}
+
+		ev.Branch = pr.Base
+		ev.Revision = pr.HeadSHA
+
+		return ev, nil
+	}
+
+	if ev.Branch == "" {
+		return ev, nil
+	}
+
+	if ev.Revision == "" {
+		ev.Revision = ev.Branch
+		return ev, nil
+	}
+
+	if repoURL == "" {
+		return ev, nil
+	}
+
+	ok, err := l.provider.BranchContains(ctx, repoURL, ev.Branch, ev.Revision)
+	if err != nil {
+		return ev, err
+	}
+	if !ok {
+		return ev, fmt.Errorf("%w: branch %s does not contain revision %s", ErrUnverified, ev.Branch, ev.Revision)
+	}
+
+	return ev, nil
+}
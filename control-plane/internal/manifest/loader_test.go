@@ -0,0 +1,248 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/providers"
+)
+
+const (
+	repoURL   = "https://github.com/acme/payments"
+	gitlabURL = "https://gitlab.com/acme/payments"
+)
+
+func TestLoad(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		repoURL     string
+		file        []byte
+		fetchErr    error
+		wantNil     bool
+		wantErr     bool
+		wantInvalid bool
+	}{
+		{
+			name:    "manifest",
+			repoURL: repoURL,
+			file:    []byte("version: 1\n"),
+		},
+		{
+			name:    "no repository",
+			repoURL: "",
+			wantNil: true,
+		},
+		{
+			name:     "no manifest",
+			repoURL:  repoURL,
+			fetchErr: providers.ErrFileNotFound,
+			wantNil:  true,
+		},
+		{
+			name:    "unsupported host",
+			repoURL: gitlabURL,
+			wantNil: true,
+		},
+		{
+			name:        "too large",
+			repoURL:     repoURL,
+			fetchErr:    fmt.Errorf("%w: 2 MiB", providers.ErrFileTooLarge),
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "malformed",
+			repoURL:     repoURL,
+			file:        []byte("version: [1"),
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:     "unreachable",
+			repoURL:  repoURL,
+			fetchErr: errors.New("connection refused"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLoader(&fakeProvider{file: tt.file, fetchErr: tt.fetchErr})
+
+			m, err := l.Load(context.Background(), tt.repoURL, "main")
+
+			switch {
+			case tt.wantErr:
+				if err == nil || errors.Is(err, ErrInvalid) != tt.wantInvalid {
+					t.Fatalf("Load() err = %v, want invalid %v", err, tt.wantInvalid)
+				}
+			case err != nil:
+				t.Fatalf("Load() = %v", err)
+			case tt.wantNil != (m == nil):
+				t.Fatalf("Load() = %+v, want nil %v", m, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+
+	provider := &fakeProvider{
+		file: []byte("version: 1\n"),
+		branches: map[string][]string{
+			"main": {"aaa", "bbb"},
+		},
+		pulls: map[int]*providers.PullRequest{
+			7: {Number: 7, Open: true, Base: "main", Head: "feature", HeadSHA: "ccc"},
+			8: {Number: 8, Base: "main", Head: "old", HeadSHA: "ddd"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		repoURL string
+		ev      Event
+		want    Event
+		wantErr error
+	}{
+		{
+			name: "api without branch",
+			ev:   Event{Name: EventAPI, Revision: "zzz"},
+			want: Event{Name: EventAPI, Revision: "zzz"},
+		},
+		{
+			name: "branch without revision runs the branch",
+			ev:   Event{Name: EventAPI, Branch: "main"},
+			want: Event{Name: EventAPI, Branch: "main", Revision: "main"},
+		},
+		{
+			name: "branch contains revision",
+			ev:   Event{Name: EventAPI, Branch: "main", Revision: "bbb"},
+			want: Event{Name: EventAPI, Branch: "main", Revision: "bbb"},
+		},
+		{
+			name:    "branch does not contain revision",
+			ev:      Event{Name: EventAPI, Branch: "main", Revision: "ccc"},
+			wantErr: ErrUnverified,
+		},
+		{
+			name: "pull request resolves branch and revision",
+			ev:   Event{Name: EventPullRequest, PullRequest: 7},
+			want: Event{Name: EventPullRequest, Branch: "main", Revision: "ccc", PullRequest: 7},
+		},
+		{
+			name:    "pull request without number",
+			ev:      Event{Name: EventPullRequest, Branch: "main"},
+			wantErr: ErrUnverified,
+		},
+		{
+			name:    "unknown pull request",
+			ev:      Event{Name: EventPullRequest, PullRequest: 9},
+			wantErr: ErrUnverified,
+		},
+		{
+			name:    "closed pull request",
+			ev:      Event{Name: EventPullRequest, PullRequest: 8},
+			wantErr: ErrUnverified,
+		},
+		{
+			name:    "pull request claimed against another base",
+			ev:      Event{Name: EventPullRequest, PullRequest: 7, Branch: "release/1"},
+			wantErr: ErrUnverified,
+		},
+		{
+			name:    "pull request claimed at another revision",
+			ev:      Event{Name: EventPullRequest, PullRequest: 7, Revision: "aaa"},
+			wantErr: ErrUnverified,
+		},
+		{
+			name:    "unsupported host is not checked",
+			repoURL: gitlabURL,
+			ev:      Event{Name: EventPullRequest, PullRequest: 9, Revision: "zzz"},
+			want:    Event{Name: EventPullRequest, PullRequest: 9, Revision: "zzz"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoURL
+			if tt.repoURL != "" {
+				repo = tt.repoURL
+			}
+
+			got, m, err := NewLoader(provider).Resolve(context.Background(), repo, tt.ev)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve() err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Resolve() = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Resolve() = %+v, want %+v", got, tt.want)
+			}
+			if (m == nil) != (repo != repoURL) {
+				t.Fatalf("Resolve() manifest = %+v", m)
+			}
+		})
+	}
+}
+
+// ---- Helpers ----
+
+// fakeProvider serves one file for every GitHub repository, branches
+// as lists of the revisions they contain, and pull requests.
+type fakeProvider struct {
+	file     []byte
+	fetchErr error
+	branches map[string][]string
+	pulls    map[int]*providers.PullRequest
+}
+
+func (p *fakeProvider) ValidateRepo(string) error { return nil }
+
+func (p *fakeProvider) DetectProjectType(string) (string, error) { return "", nil }
+
+func (p *fakeProvider) FetchFile(_ context.Context, repoURL, _, _ string) ([]byte, error) {
+	if err := p.supports(repoURL); err != nil {
+		return nil, err
+	}
+	return p.file, p.fetchErr
+}
+
+func (p *fakeProvider) BranchContains(_ context.Context, repoURL, branch, revision string) (bool, error) {
+	if err := p.supports(repoURL); err != nil {
+		return false, err
+	}
+	for _, r := range p.branches[branch] {
+		if r == revision {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *fakeProvider) PullRequest(_ context.Context, repoURL string, number int) (*providers.PullRequest, error) {
+	if err := p.supports(repoURL); err != nil {
+		return nil, err
+	}
+	pr, ok := p.pulls[number]
+	if !ok {
+		return nil, providers.ErrPullRequestNotFound
+	}
+	return pr, nil
+}
+
+func (p *fakeProvider) supports(repoURL string) error {
+	if repoURL == gitlabURL {
+		return providers.ErrUnsupportedRepository
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// FileName is where a service keeps its pipeline configuration,
+// relative to the repository root.
+const FileName = ".platform.yaml"
+
+// CurrentVersion is the only manifest schema version understood today.
+const CurrentVersion = 1
+
+// Trigger events a manifest can allow.
+const (
+	EventPush        = "push"
+	EventPullRequest = "pull_request"
+	EventAPI         = "api"
+)
+
+// ErrInvalid wraps every parse and validation failure so callers can
+// distinguish a broken manifest from an unreachable repository.
+var ErrInvalid = errors.New("invalid platform manifest")
+
+// Manifest is the pipeline-as-code contract a service carries in its
+// repository.
+//
+// Example:
+//
+//	version: 1
+//	ci:
+//	  template: node-ci-template
+//	  parameters:
+//	    node_version: "20"
+//	environments:
+//	  ttl: 4h
+//	  blueprint: namespace
+//	  parameters:
+//	    image_tag: latest
+//	triggers:
+//	  - event: push
+//	    branches: ["main", "release/*"]
+//	  - event: pull_request
+type Manifest struct {
+	Version      int                `json:"version"`
+	CI           CIConfig           `json:"ci"`
+	Environments EnvironmentsConfig `json:"environments"`
+	Triggers     []Trigger          `json:"triggers"`
+}
+
+// CIConfig overrides catalog template selection for CI runs.
+type CIConfig struct {
+	Template   string            `json:"template"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// EnvironmentsConfig holds defaults applied to environments created
+// for the service. Request parameters override Parameters.
+type EnvironmentsConfig struct {
+	TTL        string            `json:"ttl"`
+	Blueprint  string            `json:"blueprint"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// Trigger allows runs for an event, optionally restricted to branches.
+//
+// Branch patterns use path.Match syntax ("release/*"). For
+// pull_request events they match the pull request's base branch.
+type Trigger struct {
+	Event    string   `json:"event"`
+	Branches []string `json:"branches"`
+}
+
+// Parse decodes and validates a manifest.
+//
+// Unknown fields are rejected: a typo in a pipeline file should fail
+// loudly rather than be silently ignored.
+func Parse(data []byte) (*Manifest, error) {
+
+	var m Manifest
+	if err := yaml.UnmarshalStrict(data, &m); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalid, FileName, err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Validate reports every problem at once, each prefixed with the
+// offending field path.
+func (m *Manifest) Validate() error {
+
+	var problems []string
+
+	if m.Version != CurrentVersion {
+		problems = append(problems, fmt.Sprintf(
+			"version: must be %d, got %d",
+			CurrentVersion,
+			m.Version,
+		))
+	}
+
+	if m.CI.Template != "" && !dnsNamePattern.MatchString(m.CI.Template) {
+		problems = append(problems, fmt.Sprintf(
+			"ci.template: %q is not a valid template name",
+			m.CI.Template,
+		))
+	}
+
+	for k := range m.CI.Parameters {
+		if !parameterNamePattern.MatchString(k) {
+			problems = append(problems, fmt.Sprintf(
+				"ci.parameters.%s: invalid parameter name",
+				k,
+			))
+		}
+	}
+
+	for k := range m.Environments.Parameters {
+		if !parameterNamePattern.MatchString(k) {
+			problems = append(problems, fmt.Sprintf(
+				"environments.parameters.%s: invalid parameter name",
+				k,
+			))
+		}
+	}
+
+	if m.Environments.TTL != "" {
+		if ttl, err := time.ParseDuration(m.Environments.TTL); err != nil || ttl <= 0 {
+			problems = append(problems, fmt.Sprintf(
+				"environments.ttl: %q is not a positive duration",
+				m.Environments.TTL,
+			))
+		}
+	}
+
+	if m.Environments.Blueprint != "" && !dnsNamePattern.MatchString(m.Environments.Blueprint) {
+		problems = append(problems, fmt.Sprintf(
+			"environments.blueprint: %q is not a valid blueprint name",
+			m.Environments.Blueprint,
+		))
+	}
+
+	for i, t := range m.Triggers {
+		switch t.Event {
+		case EventPush, EventPullRequest, EventAPI:
+		default:
+			problems = append(problems, fmt.Sprintf(
+				"triggers[%d].event: unknown event %q",
+				i,
+				t.Event,
+			))
+		}
+
+		for j, b := range t.Branches {
+			if _, err := path.Match(b, ""); err != nil {
+				problems = append(problems, fmt.Sprintf(
+					"triggers[%d].branches[%d]: bad pattern %q",
+					i,
+					j,
+					b,
+				))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf(
+			"%w: %s: %s",
+			ErrInvalid,
+			FileName,
+			strings.Join(problems, "; "),
+		)
+	}
+
+	return nil
+}
+
+// DefaultTTL returns the environment TTL default, or zero if unset.
+//
+// Only call on a validated manifest.
+func (m *Manifest) DefaultTTL() time.Duration {
+	ttl, _ := time.ParseDuration(m.Environments.TTL)
+	return ttl
+}
+
+// Allows reports whether the trigger rules permit a run for event on
+// branch.
+//
+// A manifest without trigger rules allows everything.
+// A rule without branches matches every branch.
+func (m *Manifest) Allows(event string, branch string) bool {
+
+	if len(m.Triggers) == 0 {
+		return true
+	}
+
+	for _, t := range m.Triggers {
+		if t.Event != event {
+			continue
+		}
+
+		if len(t.Branches) == 0 {
+			return true
+		}
+
+		for _, pattern := range t.Branches {
+			if ok, _ := path.Match(pattern, branch); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+var (
+	dnsNamePattern       = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	parameterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+)
@@ -0,0 +1,134 @@
+package manifest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr []string
+	}{
+		{
+			name: "full",
+			data: `
+version: 1
+ci:
+  template: node-ci-template
+  parameters:
+    node_version: "20"
+environments:
+  ttl: 4h
+  blueprint: namespace
+  parameters:
+    image_tag: latest
+triggers:
+  - event: push
+    branches: ["main", "release/*"]
+  - event: pull_request
+`,
+		},
+		{
+			name:    "unknown field",
+			data:    "version: 1\nci:\n  templte: x\n",
+			wantErr: []string{"templte"},
+		},
+		{
+			name:    "wrong version",
+			data:    "version: 2\n",
+			wantErr: []string{"version: must be 1, got 2"},
+		},
+		{
+			name: "every problem at once",
+			data: `
+version: 1
+ci:
+  template: Not_A_Name
+  parameters:
+    "bad name": x
+environments:
+  ttl: -1h
+  blueprint: "full stack"
+  parameters:
+    "$HOME": x
+triggers:
+  - event: tag
+  - event: push
+    branches: ["[main"]
+`,
+			wantErr: []string{
+				`ci.template: "Not_A_Name"`,
+				"ci.parameters.bad name: invalid parameter name",
+				`environments.ttl: "-1h"`,
+				`environments.blueprint: "full stack"`,
+				"environments.parameters.$HOME: invalid parameter name",
+				`triggers[0].event: unknown event "tag"`,
+				`triggers[1].branches[0]: bad pattern "[main"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.data))
+
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Parse() = %v", err)
+				}
+				if m.DefaultTTL().Hours() != 4 || m.CI.Parameters["node_version"] != "20" {
+					t.Fatalf("Parse() = %+v", m)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("Parse() = %v, want ErrInvalid", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("Parse() = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+
+	m := &Manifest{
+		Triggers: []Trigger{
+			{Event: EventPush, Branches: []string{"main", "release/*"}},
+			{Event: EventPullRequest},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		manifest *Manifest
+		event    string
+		branch   string
+		want     bool
+	}{
+		{"no rules", &Manifest{}, EventAPI, "", true},
+		{"exact branch", m, EventPush, "main", true},
+		{"branch pattern", m, EventPush, "release/1.2", true},
+		{"pattern does not cross slashes", m, EventPush, "release/1.2/hotfix", false},
+		{"other branch", m, EventPush, "feature", false},
+		{"no branch", m, EventPush, "", false},
+		{"event without branches", m, EventPullRequest, "anything", true},
+		{"event not listed", m, EventAPI, "main", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.manifest.Allows(tt.event, tt.branch); got != tt.want {
+				t.Fatalf("Allows(%q, %q) = %v, want %v", tt.event, tt.branch, got, tt.want)
+			}
+		})
+	}
+}
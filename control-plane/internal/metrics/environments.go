@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+)
+
+// Environment phases, as reported by the environments gauge.
+const (
+	PhaseProvisioning = "provisioning"
+	PhaseReady        = "ready"
+	PhaseFailed       = "failed"
+	PhaseExpired      = "expired"
+	PhaseDestroyed    = "destroyed"
+	PhaseUnknown      = "unknown"
+)
+
+// EnvironmentSource lists the environments the control plane knows.
+// The environments returned must be snapshots the observer owns: it
+// reads them without synchronisation.
+type EnvironmentSource interface {
+	ListEnvironments() []*orchestrator.Environment
+}
+
+// EnvironmentObserver periodically derives environment phases from
+// their create workflows.
+//
+// Execution state is never stored by the control plane, so the gauge
+// and the time-to-ready histogram are fed by polling rather than at
+// request time. Each environment's time to ready is observed once.
+type EnvironmentObserver struct {
+	source       EnvironmentSource
+	orchestrator orchestrator.EnvironmentOrchestrator
+	logger       *zap.Logger
+
+	// ready holds create workflow UIDs whose time to ready was observed.
+	ready map[string]bool
+}
+
+func NewEnvironmentObserver(
+	source EnvironmentSource,
+	envOrchestrator orchestrator.EnvironmentOrchestrator,
+	logger *zap.Logger,
+) *EnvironmentObserver {
+	return &EnvironmentObserver{
+		source:       source,
+		orchestrator: envOrchestrator,
+		logger:       logger,
+		ready:        make(map[string]bool),
+	}
+}
+
+// Run observes every interval until ctx is cancelled.
+func (o *EnvironmentObserver) Run(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		o.Observe(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Observe makes one pass over all environments.
+func (o *EnvironmentObserver) Observe(ctx context.Context) {
+
+	now := time.Now()
+	counts := make(map[[2]string]int)
+	failures := 0
+
+	for _, env := range o.source.ListEnvironments() {
+		phase, err := o.phase(ctx, env, now)
+		if err != nil {
+			failures++
+		}
+		counts[[2]string{phase, env.Spec.Service}]++
+	}
+
+	environments.Reset()
+	for key, n := range counts {
+		environments.WithLabelValues(key[0], key[1]).Set(float64(n))
+	}
+
+	if failures > 0 {
+		o.logger.Warn("environment observer could not read some workflows",
+			zap.Int("failures", failures),
+		)
+		environmentObservations.WithLabelValues(outcomeError).Inc()
+		return
+	}
+
+	environmentObservations.WithLabelValues(outcomeSuccess).Inc()
+}
+
+func (o *EnvironmentObserver) phase(
+	ctx context.Context,
+	env *orchestrator.Environment,
+	now time.Time,
+) (string, error) {
+
+	switch {
+	case env.DestroyWorkflow != nil:
+		return PhaseDestroyed, nil
+	case now.After(env.CreateWorkflow.SubmittedAt.Add(env.Spec.TTL)):
+		return PhaseExpired, nil
+	}
+
+	status, err := o.orchestrator.GetCreateStatus(ctx, env)
+	if err != nil {
+		return PhaseUnknown, err
+	}
+
+	switch status.Phase {
+	case wf.WorkflowSucceeded:
+		o.observeReady(env, status)
+		return PhaseReady, nil
+	case wf.WorkflowFailed, wf.WorkflowError:
+		return PhaseFailed, nil
+	default:
+		return PhaseProvisioning, nil
+	}
+}
+
+func (o *EnvironmentObserver) observeReady(env *orchestrator.Environment, status *wf.WorkflowStatus) {
+
+	key := env.CreateWorkflow.UID
+	if key == "" {
+		key = env.CreateWorkflow.Cluster + "/" + env.CreateWorkflow.Namespace + "/" + env.CreateWorkflow.Name
+	}
+
+	if o.ready[key] || status.FinishedAt.IsZero() {
+		return
+	}
+	o.ready[key] = true
+
+	environmentTimeToReady.Observe(status.FinishedAt.Sub(env.CreateWorkflow.SubmittedAt).Seconds())
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+// generatedTemplate labels submissions of generated pipelines, which
+// have no WorkflowTemplate.
+const generatedTemplate = "generated"
+
+// Executor times every call to the execution plane and counts
+// submissions.
+type Executor struct {
+	inner executor.WorkflowExecutor
+}
+
+func NewExecutor(inner executor.WorkflowExecutor) *Executor {
+	return &Executor{inner: inner}
+}
+
+func (e *Executor) SubmitFromTemplate(
+	ctx context.Context,
+	target executor.Target,
+	templateName string,
+	generateName string,
+	parameters map[string]string,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	start := time.Now()
+
+	created, err := e.inner.SubmitFromTemplate(
+		ctx,
+		target,
+		templateName,
+		generateName,
+		parameters,
+		labels,
+	)
+
+	observe("submit_from_template", start, err)
+	workflowSubmissions.WithLabelValues(
+		templateName,
+		labels[executor.LabelWorkflowType],
+		outcome(err),
+	).Inc()
+
+	return created, err
+}
+
+func (e *Executor) SubmitWorkflow(
+	ctx context.Context,
+	target executor.Target,
+	workflow *wf.Workflow,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	start := time.Now()
+
+	created, err := e.inner.SubmitWorkflow(ctx, target, workflow, labels)
+
+	observe("submit_workflow", start, err)
+	workflowSubmissions.WithLabelValues(
+		generatedTemplate,
+		labels[executor.LabelWorkflowType],
+		outcome(err),
+	).Inc()
+
+	return created, err
+}
+
+func (e *Executor) GetWorkflow(
+	ctx context.Context,
+	target executor.Target,
+	name string,
+) (*wf.Workflow, error) {
+
+	start := time.Now()
+
+	workflow, err := e.inner.GetWorkflow(ctx, target, name)
+
+	observe("get_workflow", start, err)
+
+	return workflow, err
+}
+
+func (e *Executor) Cancel(
+	ctx context.Context,
+	target executor.Target,
+	name string,
+) error {
+
+	start := time.Now()
+
+	err := e.inner.Cancel(ctx, target, name)
+
+	observe("cancel", start, err)
+
+	return err
+}
+
+func observe(operation string, start time.Time, err error) {
+	executorDuration.WithLabelValues(operation, outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// WorkflowLister times list calls to the execution plane.
+type WorkflowLister struct {
+	inner executor.WorkflowLister
+}
+
+func NewWorkflowLister(inner executor.WorkflowLister) *WorkflowLister {
+	return &WorkflowLister{inner: inner}
+}
+
+func (l *WorkflowLister) ListWorkflows(
+	ctx context.Context,
+	target executor.Target,
+	opts executor.ListOptions,
+) (*executor.WorkflowList, error) {
+
+	start := time.Now()
+
+	list, err := l.inner.ListWorkflows(ctx, target, opts)
+
+	observe("list_workflows", start, err)
+
+	return list, err
+}
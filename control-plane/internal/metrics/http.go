@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RouteFunc returns the route pattern serving a request, or "" when
+// none matches. *http.ServeMux's Handler method has this shape.
+type RouteFunc func(r *http.Request) (http.Handler, string)
+
+// unmatchedRoute labels requests no route matches, so that arbitrary
+// paths cannot blow up label cardinality.
+const unmatchedRoute = "unmatched"
+
+// Middleware counts and times every request by route pattern.
+//
+// It should wrap everything else (authentication, rate limits) so
+// that rejected requests are measured too; the route is therefore
+// resolved up front rather than by the router.
+func Middleware(route RouteFunc) func(http.Handler) http.Handler {
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			start := time.Now()
+
+			pattern := unmatchedRoute
+			if _, p := route(r); p != "" {
+				pattern = p
+			}
+
+			rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			status := strconv.Itoa(rw.status)
+
+			httpRequests.WithLabelValues(pattern, r.Method, status).Inc()
+			httpDuration.WithLabelValues(pattern, r.Method, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
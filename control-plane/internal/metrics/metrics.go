@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name.
+const namespace = "control_plane"
+
+// Registry holds the control plane's metrics, plus Go runtime and
+// process metrics. It is separate from the global default registry so
+// that client libraries cannot leak metrics into /metrics.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+//
+// ---- HTTP ----
+//
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route pattern, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route pattern, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+)
+
+//
+// ---- Execution plane ----
+//
+
+var (
+	workflowSubmissions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workflow_submissions_total",
+		Help:      "Workflow submissions by template, workflow type and outcome.",
+	}, []string{"template", "type", "outcome"})
+
+	executorDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "executor_call_duration_seconds",
+		Help:      "Latency of calls to the execution plane by operation and outcome.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"operation", "outcome"})
+)
+
+//
+// ---- Environments ----
+//
+
+var (
+	environments = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "environments",
+		Help:      "Environments by phase and service, as last observed.",
+	}, []string{"phase", "service"})
+
+	environmentTimeToReady = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "environment_time_to_ready_seconds",
+		Help:      "Time from environment submission until its create workflow succeeded.",
+		Buckets:   []float64{15, 30, 60, 120, 300, 600, 900, 1800, 3600},
+	})
+
+	environmentObservations = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "environment_observer_runs_total",
+		Help:      "Passes of the environment observer by outcome.",
+	}, []string{"outcome"})
+)
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Outcome label values.
+const (
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+)
+
+func outcome(err error) string {
+	if err != nil {
+		return outcomeError
+	}
+	return outcomeSuccess
+}
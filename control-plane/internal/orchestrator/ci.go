@@ -0,0 +1,48 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/pipeline"
+)
+
+//
+// ----- DOMAIN TYPES -----
+//
+
+// RunSpec defines a requested CI run for a registered service.
+// Like EnvironmentSpec, it is intent-only.
+//
+// Template, when set, bypasses language-based catalog selection
+// (e.g. a repository manifest pinning its CI template).
+type RunSpec struct {
+	Service    string
+	Team       string
+	Language   string
+	Template   string
+	Revision   string
+	Trigger    string
+	Parameters map[string]string
+}
+
+// Run is the control-plane view of a submitted CI run.
+type Run struct {
+	Spec     RunSpec
+	Workflow WorkflowReference
+}
+
+//
+// ----- ORCHESTRATOR CONTRACT -----
+//
+
+// CIOrchestrator submits CI pipelines for services.
+//
+// Template selection is data-driven: the template is resolved from
+// the catalog by language, never hardcoded.
+type CIOrchestrator interface {
+	Run(ctx context.Context, spec RunSpec) (*Run, error)
+
+	// RunPipeline generates a Workflow from declarative stages and
+	// submits it inline. No WorkflowTemplate is involved.
+	RunPipeline(ctx context.Context, run RunSpec, spec *pipeline.Spec) (*Run, error)
+}
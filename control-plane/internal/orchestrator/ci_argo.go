@@ -0,0 +1,169 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/pipeline"
+)
+
+type ArgoCIOrchestrator struct {
+	exec      executor.WorkflowExecutor
+	templates *catalog.Catalog
+	router    *NamespaceRouter
+}
+
+func NewArgoCIOrchestrator(
+	exec executor.WorkflowExecutor,
+	templates *catalog.Catalog,
+	router *NamespaceRouter,
+) *ArgoCIOrchestrator {
+	return &ArgoCIOrchestrator{
+		exec:      exec,
+		templates: templates,
+		router:    router,
+	}
+}
+
+// Run selects the CI template registered for the service language
+// and submits a Workflow derived from it.
+func (o *ArgoCIOrchestrator) Run(
+	ctx context.Context,
+	spec RunSpec,
+) (*Run, error) {
+
+	template, err := o.resolveTemplate(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := spec.Trigger
+	if trigger == "" {
+		trigger = TriggerAPI
+	}
+
+	//-----------------------------------------
+	// Parameters (template-facing)
+	//-----------------------------------------
+
+	params := make(map[string]string, len(spec.Parameters)+2)
+	for k, v := range spec.Parameters {
+		params[k] = v
+	}
+
+	params[ParamService] = spec.Service
+	if spec.Revision != "" {
+		params[ParamRevision] = spec.Revision
+	}
+
+	//-----------------------------------------
+	// Labels
+	//-----------------------------------------
+
+	labels := NewLabelBuilder(
+		WorkflowTypeCI,
+		spec.Service,
+	).
+		WithTrigger(trigger).
+		Build()
+
+	//-----------------------------------------
+	// Submit CI workflow
+	//-----------------------------------------
+
+	wfObj, err := o.exec.SubmitFromTemplate(
+		ctx,
+		o.target(spec),
+		template.Name,
+		"ci-run-",
+		params,
+		labels,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("submit ci workflow: %w", err)
+	}
+
+	return &Run{
+		Spec:     spec,
+		Workflow: toWorkflowReference(wfObj, ""),
+	}, nil
+}
+
+// RunPipeline generates and submits a declarative pipeline.
+//
+// Only run.Service and run.Team are used; the template is always
+// executor.GeneratedTemplate.
+func (o *ArgoCIOrchestrator) RunPipeline(
+	ctx context.Context,
+	run RunSpec,
+	spec *pipeline.Spec,
+) (*Run, error) {
+
+	workflow, err := pipeline.Generate(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	run.Template = executor.GeneratedTemplate
+	run.Trigger = TriggerAPI
+
+	labels := NewLabelBuilder(
+		WorkflowTypeCI,
+		run.Service,
+	).
+		WithTrigger(run.Trigger).
+		Build()
+
+	wfObj, err := o.exec.SubmitWorkflow(
+		ctx,
+		o.target(run),
+		workflow,
+		labels,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("submit pipeline workflow: %w", err)
+	}
+
+	return &Run{
+		Spec:     run,
+		Workflow: toWorkflowReference(wfObj, ""),
+	}, nil
+}
+
+func (o *ArgoCIOrchestrator) resolveTemplate(spec RunSpec) (catalog.Template, error) {
+
+	if spec.Template != "" {
+		template, err := o.templates.Get(spec.Template)
+		if err != nil {
+			return catalog.Template{}, err
+		}
+
+		if template.Purpose != catalog.PurposeCI {
+			return catalog.Template{}, fmt.Errorf(
+				"%w: template %s is labelled for %s, not %s",
+				catalog.ErrTemplateNotFound,
+				template.Name,
+				template.Purpose,
+				catalog.PurposeCI,
+			)
+		}
+
+		return template, nil
+	}
+
+	if spec.Language == "" {
+		return catalog.Template{}, fmt.Errorf("service %s has no language", spec.Service)
+	}
+
+	return o.templates.Resolve(catalog.PurposeCI, spec.Language)
+}
+
+// target places CI workflows on the default cluster; only
+// environments are spread across clusters.
+func (o *ArgoCIOrchestrator) target(spec RunSpec) executor.Target {
+	return executor.Target{
+		Namespace: o.router.Resolve(spec.Service, spec.Team),
+	}
+}
@@ -0,0 +1,81 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+func TestCIResolveTemplate(t *testing.T) {
+
+	templates := catalog.New(&fakeTemplates{templates: []wf.WorkflowTemplate{
+		catalogTemplate("node-ci", catalog.PurposeCI, "node"),
+		catalogTemplate("env-create", catalog.PurposeEnvCreate, ""),
+	}}, "argo")
+	if err := templates.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	o := NewArgoCIOrchestrator(nil, templates, nil)
+
+	tests := []struct {
+		name     string
+		spec     RunSpec
+		want     string
+		notFound bool
+	}{
+		{name: "by language", spec: RunSpec{Service: "web", Language: "node"}, want: "node-ci"},
+		{name: "by name", spec: RunSpec{Service: "web", Template: "node-ci"}, want: "node-ci"},
+		{name: "unknown name", spec: RunSpec{Service: "web", Template: "go-ci"}, notFound: true},
+		{name: "wrong purpose", spec: RunSpec{Service: "web", Template: "env-create"}, notFound: true},
+		{name: "unknown language", spec: RunSpec{Service: "web", Language: "rust"}, notFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := o.resolveTemplate(tt.spec)
+
+			if tt.notFound {
+				if !errors.Is(err, catalog.ErrTemplateNotFound) {
+					t.Fatalf("resolveTemplate() = %v, want ErrTemplateNotFound", err)
+				}
+				return
+			}
+
+			if err != nil || got.Name != tt.want {
+				t.Fatalf("resolveTemplate() = %q, %v, want %q", got.Name, err, tt.want)
+			}
+		})
+	}
+}
+
+// ---- Helpers ----
+
+type fakeTemplates struct {
+	executor.TemplateReader
+
+	templates []wf.WorkflowTemplate
+}
+
+func (f *fakeTemplates) ListTemplates(context.Context, executor.Target, string) ([]wf.WorkflowTemplate, error) {
+	return f.templates, nil
+}
+
+func catalogTemplate(name string, purpose catalog.Purpose, language string) wf.WorkflowTemplate {
+	return wf.WorkflowTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				catalog.LabelCatalog:  "true",
+				catalog.LabelPurpose:  string(purpose),
+				catalog.LabelLanguage: language,
+			},
+		},
+	}
+}
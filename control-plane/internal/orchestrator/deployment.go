@@ -0,0 +1,134 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+)
+
+//
+// ----- DOMAIN TYPES -----
+//
+
+// DeploymentSpec requests a build of a registered service be deployed
+// into an environment. Like RunSpec, it is intent-only.
+//
+// Image is what the deploy template rolls out: an image reference or
+// another artifact the template understands (e.g. a chart version).
+// Template, when set, bypasses catalog selection.
+type DeploymentSpec struct {
+	Service    string
+	Image      string
+	Revision   string
+	Template   string
+	Trigger    string
+	Parameters map[string]string
+}
+
+// ErrInvalidDeployment is returned for deployment specs that deploy
+// templates cannot safely be given.
+var ErrInvalidDeployment = errors.New("invalid deployment")
+
+var (
+	// imagePattern is an image reference: [registry[:port]/]path[:tag][@digest].
+	imagePattern = regexp.MustCompile(
+		`^([A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?(:[0-9]+)?/)?` +
+			`[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*` +
+			`(:[A-Za-z0-9_][A-Za-z0-9_.-]{0,127})?` +
+			`(@sha256:[a-f0-9]{64})?$`,
+	)
+
+	// revisionPattern is a commit SHA, tag or branch name.
+	revisionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]{0,254}$`)
+
+	// parameterValuePattern admits chart references, versions and
+	// the like, but nothing a shell or YAML would interpret.
+	parameterValuePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/@+=-]{0,511}$`)
+)
+
+// Validate checks the values that reach deploy templates. Templates
+// pass them to their scripts as quoted environment variables; this is
+// the second line of defence, and keeps manifests they render valid.
+func (s DeploymentSpec) Validate() error {
+
+	var problems []string
+
+	if !imagePattern.MatchString(s.Image) {
+		problems = append(problems, fmt.Sprintf("image %q is not an image reference", s.Image))
+	}
+
+	if s.Revision != "" && !revisionPattern.MatchString(s.Revision) {
+		problems = append(problems, fmt.Sprintf("revision %q is not a commit, tag or branch", s.Revision))
+	}
+
+	for name, value := range s.Parameters {
+		if value != "" && !parameterValuePattern.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("parameter %s contains characters that are not allowed", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%w: %s", ErrInvalidDeployment, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// Deployment is the control-plane view of a submitted deployment.
+//
+// Phase is recorded once the workflow has completed, so that history
+// and the deployed versions survive the workflow being garbage
+// collected. Until then it is empty and the workflow is queried.
+type Deployment struct {
+	Spec      DeploymentSpec
+	Namespace string
+	Workflow  WorkflowReference
+	Phase     wf.WorkflowPhase
+}
+
+// Completed reports whether the deployment's outcome is recorded.
+func (d *Deployment) Completed() bool {
+	return d.Phase != ""
+}
+
+// Deployed returns, per service, the latest deployment into the
+// environment that succeeded.
+func (e *Environment) Deployed() map[string]*Deployment {
+
+	deployed := make(map[string]*Deployment)
+
+	for i := len(e.Deployments) - 1; i >= 0; i-- {
+		d := e.Deployments[i]
+		if d.Phase != wf.WorkflowSucceeded {
+			continue
+		}
+		if _, ok := deployed[d.Spec.Service]; !ok {
+			deployed[d.Spec.Service] = d
+		}
+	}
+
+	return deployed
+}
+
+//
+// ----- ORCHESTRATOR CONTRACT -----
+//
+
+// DeploymentOrchestrator deploys service builds into environments.
+//
+// Template selection is data-driven, as for CI: the deploy template
+// (kubectl, Helm, Kustomize, ...) is resolved from the catalog.
+type DeploymentOrchestrator interface {
+	// Deploy submits a deployment into env. Checking that env is
+	// ready, and recording the deployment in its history, is left to
+	// the caller.
+	Deploy(ctx context.Context, env *Environment, spec DeploymentSpec) (*Deployment, error)
+
+	GetDeploymentStatus(ctx context.Context, d *Deployment) (*wf.WorkflowStatus, error)
+}
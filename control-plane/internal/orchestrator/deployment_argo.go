@@ -0,0 +1,154 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+type ArgoDeploymentOrchestrator struct {
+	exec      executor.WorkflowExecutor
+	templates *catalog.Catalog
+}
+
+func NewArgoDeploymentOrchestrator(
+	exec executor.WorkflowExecutor,
+	templates *catalog.Catalog,
+) *ArgoDeploymentOrchestrator {
+	return &ArgoDeploymentOrchestrator{
+		exec:      exec,
+		templates: templates,
+	}
+}
+
+// Deploy submits the deploy template next to the environment's create
+// workflow, on the environment's cluster, whatever routing says now.
+func (o *ArgoDeploymentOrchestrator) Deploy(
+	ctx context.Context,
+	env *Environment,
+	spec DeploymentSpec,
+) (*Deployment, error) {
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	template, err := o.resolveTemplate(spec.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rejectPlatformParameters(template, spec.Parameters); err != nil {
+		return nil, err
+	}
+	if err := template.ValidateUserParameters(spec.Parameters); err != nil {
+		return nil, err
+	}
+
+	trigger := spec.Trigger
+	if trigger == "" {
+		trigger = TriggerAPI
+	}
+
+	namespace := env.Namespace()
+
+	//-----------------------------------------
+	// Parameters (template-facing)
+	//-----------------------------------------
+
+	params := make(map[string]string, len(spec.Parameters)+4)
+	for k, v := range spec.Parameters {
+		params[k] = v
+	}
+
+	params[ParamService] = spec.Service
+	params[ParamImage] = spec.Image
+	params[ParamRevision] = spec.Revision
+	params[ParamNamespace] = namespace
+
+	//-----------------------------------------
+	// Labels
+	//-----------------------------------------
+
+	labels := NewLabelBuilder(
+		WorkflowTypeDeploy,
+		spec.Service,
+	).
+		WithEnvironment(env.Spec.Name).
+		WithTrigger(trigger).
+		Build()
+
+	//-----------------------------------------
+	// Submit DEPLOY workflow
+	//-----------------------------------------
+
+	wfObj, err := o.exec.SubmitFromTemplate(
+		ctx,
+		env.CreateWorkflow.Target(),
+		template.Name,
+		"deploy-",
+		params,
+		labels,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("submit deploy workflow: %w", err)
+	}
+
+	spec.Template = template.Name
+	spec.Trigger = trigger
+
+	return &Deployment{
+		Spec:      spec,
+		Namespace: namespace,
+		Workflow:  toWorkflowReference(wfObj, env.CreateWorkflow.Cluster),
+	}, nil
+}
+
+func (o *ArgoDeploymentOrchestrator) GetDeploymentStatus(
+	ctx context.Context,
+	d *Deployment,
+) (*wf.WorkflowStatus, error) {
+
+	w, err := o.exec.GetWorkflow(
+		ctx,
+		d.Workflow.Target(),
+		d.Workflow.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &w.Status, nil
+}
+
+//
+// ---- Helpers ----
+//
+
+func (o *ArgoDeploymentOrchestrator) resolveTemplate(name string) (catalog.Template, error) {
+
+	if name == "" {
+		return o.templates.Resolve(catalog.PurposeDeploy, "")
+	}
+
+	t, err := o.templates.Get(name)
+	if err != nil {
+		return catalog.Template{}, err
+	}
+
+	if t.Purpose != catalog.PurposeDeploy {
+		return catalog.Template{}, fmt.Errorf(
+			"%w: template %s is labelled for %s, not %s",
+			catalog.ErrTemplateNotFound,
+			name,
+			t.Purpose,
+			catalog.PurposeDeploy,
+		)
+	}
+
+	return t, nil
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
 )
 
 //
@@ -13,10 +16,17 @@ import (
 
 // EnvironmentSpec defines the desired environment.
 // This remains intent-only.
+//
+// Blueprint selects the kind of environment; "" selects the default
+// blueprint.
 type EnvironmentSpec struct {
 	Name       string
 	Service    string
+	Team       string
+	Cluster    string
+	Blueprint  string
 	TTL        time.Duration
+	Trigger    string
 	Parameters map[string]string
 }
 
@@ -31,6 +41,7 @@ type WorkflowReference struct {
 }*/
 type WorkflowReference struct {
 	Name        string `json:"name"`
+	Cluster     string `json:"cluster"`
 	Namespace   string `json:"namespace"`
 	UID         string `json:"uid"`
 	Template    string
@@ -39,12 +50,82 @@ type WorkflowReference struct {
 
 // Environment represents the control-plane view of an environment.
 // It contains intent + references, but no execution state.
+//
+// DestroyTemplate and Terraform are resolved when the environment is
+// created, so that changing a blueprint later does not change how
+// existing environments are torn down.
+//
+// DeclaredOutputs are the blueprint's output declarations at creation.
+// Outputs are captured from the create workflow once it has succeeded
+// (see CaptureOutputs) and are nil until then.
+//
+// Deployments is the environment's deployment history, oldest first.
 type Environment struct {
 	Spec EnvironmentSpec
 
+	DestroyTemplate string
+	Terraform       *TerraformState
+	DeclaredOutputs []blueprint.Output
+	Outputs         map[string]Output
+
 	CreateWorkflow  WorkflowReference
 	DestroyWorkflow *WorkflowReference
 	TTLWorkflow     *WorkflowReference
+
+	Deployments []*Deployment
+}
+
+// Clone returns a deep copy of the environment, so that stores can
+// hand out snapshots that callers may read and change freely.
+func (e *Environment) Clone() *Environment {
+
+	out := *e
+
+	out.Spec.Parameters = cloneStrings(e.Spec.Parameters)
+	out.DeclaredOutputs = append([]blueprint.Output(nil), e.DeclaredOutputs...)
+
+	if e.Terraform != nil {
+		tf := *e.Terraform
+		tf.Variables = cloneStrings(e.Terraform.Variables)
+		out.Terraform = &tf
+	}
+
+	if e.Outputs != nil {
+		out.Outputs = make(map[string]Output, len(e.Outputs))
+		for k, v := range e.Outputs {
+			out.Outputs[k] = v
+		}
+	}
+
+	if e.DestroyWorkflow != nil {
+		ref := *e.DestroyWorkflow
+		out.DestroyWorkflow = &ref
+	}
+
+	if e.TTLWorkflow != nil {
+		ref := *e.TTLWorkflow
+		out.TTLWorkflow = &ref
+	}
+
+	if e.Deployments != nil {
+		out.Deployments = make([]*Deployment, len(e.Deployments))
+		for i, d := range e.Deployments {
+			c := *d
+			c.Spec.Parameters = cloneStrings(d.Spec.Parameters)
+			out.Deployments[i] = &c
+		}
+	}
+
+	return &out
+}
+
+// Namespace is where the environment's workloads run: its "namespace"
+// output when the create workflow reported one, its name otherwise.
+func (e *Environment) Namespace() string {
+	if o, ok := e.Outputs[ParamNamespace]; ok && o.Value != "" {
+		return o.Value
+	}
+	return e.Spec.Name
 }
 
 //
@@ -73,8 +154,15 @@ type EnvironmentOrchestrator interface {
 	Create(ctx context.Context, spec EnvironmentSpec) (*Environment, error)
 
 	// Destroy submits intent to destroy an environment.
+	//
+	// The destroy workflow runs in the namespace the environment
+	// was created from, regardless of current routing.
 	//Destroy(ctx context.Context, name string) (*WorkflowReference, error)
-	Destroy(ctx context.Context, name string, service string) (*WorkflowReference, error)
+	Destroy(ctx context.Context, env *Environment) (*WorkflowReference, error)
+
+	// Extend replaces the TTL cleanup workflow with one that expires
+	// the environment ttl after it was created.
+	Extend(ctx context.Context, env *Environment, ttl time.Duration) (*WorkflowReference, error)
 
 	// GetCreateStatus returns the current status of the create workflow.
 	//GetCreateStatus(ctx context.Context, env *Environment) (*WorkflowStatusView, error)
@@ -86,3 +174,28 @@ type EnvironmentOrchestrator interface {
 
 	GetTTLStatus(ctx context.Context, env *Environment) (*wf.WorkflowStatus, error)
 }
+
+// Target returns where the referenced workflow lives.
+func (r WorkflowReference) Target() executor.Target {
+	return executor.Target{
+		Cluster:   r.Cluster,
+		Namespace: r.Namespace,
+	}
+}
+
+//
+// ---- Helpers ----
+//
+
+func cloneStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+
+	return out
+}
@@ -7,23 +7,37 @@ import (
 
 	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
 
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
 	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
 )
 
 type ArgoEnvironmentOrchestrator struct {
-	exec executor.WorkflowExecutor
+	exec       executor.WorkflowExecutor
+	templates  *catalog.Catalog
+	blueprints *blueprint.Registry
+	router     *NamespaceRouter
+	placer     *ClusterPlacer
 }
 
 func NewArgoEnvironmentOrchestrator(
 	exec executor.WorkflowExecutor,
+	templates *catalog.Catalog,
+	blueprints *blueprint.Registry,
+	router *NamespaceRouter,
+	placer *ClusterPlacer,
 ) *ArgoEnvironmentOrchestrator {
 	return &ArgoEnvironmentOrchestrator{
-		exec: exec,
+		exec:       exec,
+		templates:  templates,
+		blueprints: blueprints,
+		router:     router,
+		placer:     placer,
 	}
 }
 
 // Create submits intent to:
-//  1. Create an environment namespace
+//  1. Create an environment from its blueprint's create template
 //  2. Schedule TTL cleanup
 //
 // The control plane stores ONLY workflow references.
@@ -33,18 +47,78 @@ func (e *ArgoEnvironmentOrchestrator) Create(
 	spec EnvironmentSpec,
 ) (*Environment, error) {
 
+	bp, err := e.blueprints.Get(spec.Blueprint)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.Blueprint = bp.Name
+
+	createTemplate, err := e.resolveTemplate(bp.CreateTemplate, catalog.PurposeEnvCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	ttlTemplate, err := e.resolveTemplate(bp.TTLTemplate, catalog.PurposeEnvTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolved now so that destroy cannot fail for lack of a template
+	// an environment was already created without.
+	destroyTemplate, err := e.resolveTemplate(bp.DestroyTemplate, catalog.PurposeEnvDestroy)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := e.placer.Place(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.Cluster = cluster
+
+	target := executor.Target{
+		Cluster:   cluster,
+		Namespace: e.router.Resolve(spec.Service, spec.Team),
+	}
+
 	expiresAt := time.Now().Add(spec.TTL).Format(time.RFC3339)
 
+	trigger := spec.Trigger
+	if trigger == "" {
+		trigger = TriggerAPI
+	}
+
 	//-----------------------------------------
 	// Parameters (template-facing)
 	//-----------------------------------------
 
-	createParams := map[string]string{
-		"env_name":   spec.Name,
-		"service":    spec.Service,
-		"expires_at": expiresAt,
+	if err := validateUserParameters(bp, createTemplate, spec.Parameters); err != nil {
+		return nil, err
 	}
 
+	createParams := make(map[string]string, len(spec.Parameters)+3)
+
+	// User parameters are Terraform variables for Terraform-backed
+	// blueprints and template parameters otherwise.
+	var tf *TerraformState
+	if bp.Terraform != nil {
+		tf = newTerraformState(bp.Terraform, spec, expiresAt)
+		if err := tf.addParameters(createParams); err != nil {
+			return nil, err
+		}
+	} else {
+		for k, v := range spec.Parameters {
+			createParams[k] = v
+		}
+	}
+
+	// Platform-controlled; validateUserParameters rejects overrides.
+	createParams[ParamEnvName] = spec.Name
+	createParams[ParamService] = spec.Service
+	createParams[ParamExpiresAt] = expiresAt
+
 	//-----------------------------------------
 	// Labels (BUILDER — NO INLINE MAPS)
 	//-----------------------------------------
@@ -54,8 +128,7 @@ func (e *ArgoEnvironmentOrchestrator) Create(
 		spec.Service,
 	).
 		WithEnvironment(spec.Name).
-		WithTrigger(TriggerAPI).
-		WithTemplate("env-create-template").
+		WithTrigger(trigger).
 		Build()
 
 	//-----------------------------------------
@@ -64,7 +137,8 @@ func (e *ArgoEnvironmentOrchestrator) Create(
 
 	createWf, err := e.exec.SubmitFromTemplate(
 		ctx,
-		"env-create-template",
+		target,
+		createTemplate.Name,
 		"env-create-",
 		createParams,
 		createLabels,
@@ -78,8 +152,14 @@ func (e *ArgoEnvironmentOrchestrator) Create(
 	//-----------------------------------------
 
 	ttlParams := map[string]string{
-		"env_name":   spec.Name,
-		"expires_at": expiresAt,
+		ParamEnvName:   spec.Name,
+		ParamExpiresAt: expiresAt,
+	}
+
+	if tf != nil {
+		if err := tf.addParameters(ttlParams); err != nil {
+			return nil, err
+		}
 	}
 
 	ttlLabels := NewLabelBuilder(
@@ -88,12 +168,12 @@ func (e *ArgoEnvironmentOrchestrator) Create(
 	).
 		WithEnvironment(spec.Name).
 		WithTrigger(TriggerSystem).
-		WithTemplate("env-ttl-cleanup-template").
 		Build()
 
 	ttlWf, err := e.exec.SubmitFromTemplate(
 		ctx,
-		"env-ttl-cleanup-template",
+		target,
+		ttlTemplate.Name,
 		"env-ttl-",
 		ttlParams,
 		ttlLabels,
@@ -109,8 +189,12 @@ func (e *ArgoEnvironmentOrchestrator) Create(
 	env := &Environment{
 		Spec: spec,
 
-		CreateWorkflow: toWorkflowReference(createWf),
-		TTLWorkflow:    toWorkflowReferencePtr(ttlWf),
+		DestroyTemplate: destroyTemplate.Name,
+		Terraform:       tf,
+		DeclaredOutputs: bp.Outputs,
+
+		CreateWorkflow: toWorkflowReference(createWf, cluster),
+		TTLWorkflow:    toWorkflowReferencePtr(ttlWf, cluster),
 	}
 
 	return env, nil
@@ -119,12 +203,33 @@ func (e *ArgoEnvironmentOrchestrator) Create(
 // Destroy submits intent to delete an environment.
 func (e *ArgoEnvironmentOrchestrator) Destroy(
 	ctx context.Context,
-	name string,
-	service string,
+	env *Environment,
 ) (*WorkflowReference, error) {
 
+	name := env.Spec.Name
+	service := env.Spec.Service
+
+	// Environments created before blueprints did not record their
+	// destroy template.
+	destroyTemplate := env.DestroyTemplate
+	if destroyTemplate == "" {
+		t, err := e.templates.Resolve(catalog.PurposeEnvDestroy, "")
+		if err != nil {
+			return nil, err
+		}
+		destroyTemplate = t.Name
+	}
+
 	params := map[string]string{
-		"env_name": name,
+		ParamEnvName: name,
+	}
+
+	// Same module version and state as apply, whatever the blueprint
+	// says now.
+	if env.Terraform != nil {
+		if err := env.Terraform.addParameters(params); err != nil {
+			return nil, err
+		}
 	}
 
 	labels := NewLabelBuilder(
@@ -133,12 +238,12 @@ func (e *ArgoEnvironmentOrchestrator) Destroy(
 	).
 		WithEnvironment(name).
 		WithTrigger(TriggerAPI).
-		WithTemplate("env-destroy-template").
 		Build()
 
 	wfObj, err := e.exec.SubmitFromTemplate(
 		ctx,
-		"env-destroy-template",
+		env.CreateWorkflow.Target(),
+		destroyTemplate,
 		"env-destroy-",
 		params,
 		labels,
@@ -147,7 +252,83 @@ func (e *ArgoEnvironmentOrchestrator) Destroy(
 		return nil, fmt.Errorf("submit env destroy workflow: %w", err)
 	}
 
-	ref := toWorkflowReference(wfObj)
+	ref := toWorkflowReference(wfObj, env.CreateWorkflow.Cluster)
+
+	return &ref, nil
+}
+
+// Extend submits a new TTL cleanup workflow expiring the environment
+// ttl after its creation, then cancels the previous one. The new
+// cleanup is submitted first, so that a failure leaves the environment
+// with the old one rather than with none.
+func (e *ArgoEnvironmentOrchestrator) Extend(
+	ctx context.Context,
+	env *Environment,
+	ttl time.Duration,
+) (*WorkflowReference, error) {
+
+	// The template the environment was created with, whatever its
+	// blueprint says now.
+	var ttlTemplate string
+	if env.TTLWorkflow != nil {
+		ttlTemplate = env.TTLWorkflow.Template
+	}
+
+	if ttlTemplate == "" {
+		bp, err := e.blueprints.Get(env.Spec.Blueprint)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := e.resolveTemplate(bp.TTLTemplate, catalog.PurposeEnvTTL)
+		if err != nil {
+			return nil, err
+		}
+		ttlTemplate = t.Name
+	}
+
+	params := map[string]string{
+		ParamEnvName:   env.Spec.Name,
+		ParamExpiresAt: env.CreateWorkflow.SubmittedAt.Add(ttl).Format(time.RFC3339),
+	}
+
+	if env.Terraform != nil {
+		if err := env.Terraform.addParameters(params); err != nil {
+			return nil, err
+		}
+	}
+
+	labels := NewLabelBuilder(
+		WorkflowTypeEnvTTL,
+		env.Spec.Service,
+	).
+		WithEnvironment(env.Spec.Name).
+		WithTrigger(TriggerSystem).
+		Build()
+
+	target := env.CreateWorkflow.Target()
+
+	ttlWf, err := e.exec.SubmitFromTemplate(
+		ctx,
+		target,
+		ttlTemplate,
+		"env-ttl-",
+		params,
+		labels,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("submit ttl workflow: %w", err)
+	}
+
+	if env.TTLWorkflow != nil {
+		if err := e.exec.Cancel(ctx, env.TTLWorkflow.Target(), env.TTLWorkflow.Name); err != nil {
+			// Best effort: the old cleanup still stands.
+			_ = e.exec.Cancel(ctx, target, ttlWf.Name)
+			return nil, fmt.Errorf("cancel ttl workflow %s: %w", env.TTLWorkflow.Name, err)
+		}
+	}
+
+	ref := toWorkflowReference(ttlWf, env.CreateWorkflow.Cluster)
 
 	return &ref, nil
 }
@@ -163,6 +344,7 @@ func (e *ArgoEnvironmentOrchestrator) GetCreateStatus(
 
 	w, err := e.exec.GetWorkflow(
 		ctx,
+		env.CreateWorkflow.Target(),
 		env.CreateWorkflow.Name,
 	)
 	if err != nil {
@@ -183,6 +365,7 @@ func (e *ArgoEnvironmentOrchestrator) GetTTLStatus(
 
 	w, err := e.exec.GetWorkflow(
 		ctx,
+		env.TTLWorkflow.Target(),
 		env.TTLWorkflow.Name,
 	)
 	if err != nil {
@@ -196,15 +379,48 @@ func (e *ArgoEnvironmentOrchestrator) GetTTLStatus(
 // ---- Helpers (DO NOT INLINE THESE) ----
 //
 
-func toWorkflowReference(w *wf.Workflow) WorkflowReference {
+// resolveTemplate returns the named template, or the catalog's
+// template for purpose when name is empty. A named template must be
+// labelled for the purpose it is used for.
+func (e *ArgoEnvironmentOrchestrator) resolveTemplate(
+	name string,
+	purpose catalog.Purpose,
+) (catalog.Template, error) {
+
+	if name == "" {
+		return e.templates.Resolve(purpose, "")
+	}
+
+	t, err := e.templates.Get(name)
+	if err != nil {
+		return catalog.Template{}, err
+	}
+
+	if t.Purpose != purpose {
+		return catalog.Template{}, fmt.Errorf(
+			"%w: template %s is labelled for %s, not %s",
+			catalog.ErrTemplateNotFound,
+			name,
+			t.Purpose,
+			purpose,
+		)
+	}
+
+	return t, nil
+}
+
+func toWorkflowReference(w *wf.Workflow, cluster string) WorkflowReference {
 	return WorkflowReference{
-		Name:      w.Name,
-		Namespace: w.Namespace,
-		UID:       string(w.UID),
+		Name:        w.Name,
+		Cluster:     cluster,
+		Namespace:   w.Namespace,
+		UID:         string(w.UID),
+		Template:    w.Labels[LabelWorkflowTemplate],
+		SubmittedAt: w.CreationTimestamp.Time,
 	}
 }
 
-func toWorkflowReferencePtr(w *wf.Workflow) *WorkflowReference {
-	ref := toWorkflowReference(w)
+func toWorkflowReferencePtr(w *wf.Workflow, cluster string) *WorkflowReference {
+	ref := toWorkflowReference(w, cluster)
 	return &ref
 }
@@ -16,21 +16,26 @@ func BaseWorkflowLabels(
 */
 package orchestrator
 
+import "github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+
 //
 // Platform Label Keys
 //
 // NEVER change these casually.
 // Treat them as part of your control-plane contract.
 //
+// Control-plane, executor and template labels are owned by the
+// executor (see executor.applyLabelContract) and cannot be set here.
+//
 
 const (
-	LabelControlPlane     = "platform.control-plane"
-	LabelExecutor         = "platform.executor"
-	LabelWorkflowType     = "platform.workflow.type"
-	LabelService          = "platform.service"
+	LabelControlPlane     = executor.LabelControlPlane
+	LabelExecutor         = executor.LabelExecutor
+	LabelWorkflowType     = executor.LabelWorkflowType
+	LabelService          = executor.LabelService
 	LabelEnvironment      = "platform.environment"
 	LabelTrigger          = "platform.trigger"
-	LabelWorkflowTemplate = "platform.workflow.template"
+	LabelWorkflowTemplate = executor.LabelWorkflowTemplate
 )
 
 //
@@ -41,6 +46,8 @@ const (
 	WorkflowTypeEnvCreate  = "environment-create"
 	WorkflowTypeEnvDestroy = "environment-destroy"
 	WorkflowTypeEnvTTL     = "environment-ttl"
+	WorkflowTypeCI         = "ci"
+	WorkflowTypeDeploy     = "deploy"
 )
 
 //
@@ -51,6 +58,7 @@ const (
 	TriggerAPI    = "api"
 	TriggerSystem = "system"
 	TriggerPR     = "pull_request" // Phase 8 ready
+	TriggerPush   = "push"
 )
 
 //
@@ -68,8 +76,6 @@ func NewLabelBuilder(
 
 	return &LabelBuilder{
 		labels: map[string]string{
-			LabelControlPlane: "true",
-			LabelExecutor:     "argo",
 			LabelWorkflowType: workflowType,
 			LabelService:      service,
 		},
@@ -86,11 +92,6 @@ func (b *LabelBuilder) WithTrigger(trigger string) *LabelBuilder {
 	return b
 }
 
-func (b *LabelBuilder) WithTemplate(template string) *LabelBuilder {
-	b.labels[LabelWorkflowTemplate] = template
-	return b
-}
-
 //
 // Build returns a defensive copy.
 //
@@ -0,0 +1,148 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+)
+
+// Output is a value reported by an environment's create workflow,
+// typed by the blueprint's declaration of it.
+//
+// Secret outputs are references only: Value is the name of a Secret in
+// the environment, never its content.
+type Output struct {
+	Type        blueprint.OutputType `json:"type"`
+	Value       string               `json:"value"`
+	Reference   bool                 `json:"reference,omitempty"`
+	Description string               `json:"description,omitempty"`
+}
+
+// CaptureOutputs reads the outputs of the environment's succeeded
+// create workflow: the workflow's output parameters, the output
+// parameters of its nodes that the blueprint declares and, for
+// Terraform-backed environments, the declared Terraform outputs. Steps
+// report intermediate values as node outputs too; only the workflow
+// decides what else is exposed.
+//
+// Outputs the blueprint declares take its type; others are strings.
+// Outputs that cannot be kept (a secret output that is not a Secret
+// name, say) are left out and reported in the error, alongside the
+// outputs that could be. The result is never nil, so that callers can
+// tell captured environments apart.
+func CaptureOutputs(
+	env *Environment,
+	status *wf.WorkflowStatus,
+) (map[string]Output, error) {
+
+	declared := make(map[string]blueprint.Output, len(env.DeclaredOutputs))
+	for _, o := range env.DeclaredOutputs {
+		declared[o.Name] = o
+	}
+
+	values := make(map[string]string)
+	addParameters(values, status.Outputs)
+
+	nodes := nodeParameters(status)
+
+	var problems []string
+
+	raw, ok := values[OutputTerraform]
+	if !ok {
+		raw, ok = nodes[OutputTerraform]
+	}
+	delete(values, OutputTerraform)
+
+	if ok {
+		tf, err := terraformOutputs(raw)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		for name, v := range tf {
+			if _, ok := declared[name]; ok {
+				values[name] = v
+			}
+		}
+	}
+
+	// Workflow outputs win over node outputs of the same name.
+	for name := range declared {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		if v, ok := nodes[name]; ok {
+			values[name] = v
+		}
+	}
+
+	outputs := make(map[string]Output, len(values))
+
+	for name, value := range values {
+		out := Output{Type: blueprint.OutputString, Value: value}
+
+		if d, ok := declared[name]; ok {
+			out.Type = d.Type
+			out.Description = d.Description
+		}
+
+		if out.Type == blueprint.OutputSecret {
+			if errs := validation.IsDNS1123Subdomain(value); len(errs) > 0 {
+				problems = append(problems, fmt.Sprintf("%s: secret outputs must name a Secret", name))
+				continue
+			}
+			out.Reference = true
+		}
+
+		outputs[name] = out
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return outputs, errors.New(strings.Join(problems, "; "))
+	}
+
+	return outputs, nil
+}
+
+//
+// ---- Helpers ----
+//
+
+// nodeParameters collects the output parameters of the workflow's
+// nodes. Nodes are read in ID order so that a name reported twice
+// resolves the same way on every read.
+func nodeParameters(status *wf.WorkflowStatus) map[string]string {
+
+	values := make(map[string]string)
+
+	ids := make([]string, 0, len(status.Nodes))
+	for id := range status.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		addParameters(values, status.Nodes[id].Outputs)
+	}
+
+	return values
+}
+
+func addParameters(values map[string]string, outputs *wf.Outputs) {
+
+	if outputs == nil {
+		return
+	}
+
+	for _, p := range outputs.Parameters {
+		if p.Value != nil {
+			values[p.Name] = p.Value.String()
+		}
+	}
+}
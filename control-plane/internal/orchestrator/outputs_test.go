@@ -0,0 +1,142 @@
+package orchestrator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+)
+
+func TestCaptureOutputs(t *testing.T) {
+
+	declared := []blueprint.Output{
+		{Name: "url", Type: blueprint.OutputURL, Description: "Ingress URL"},
+		{Name: "db_password", Type: blueprint.OutputSecret},
+		{Name: "db_host", Type: blueprint.OutputString},
+	}
+
+	tests := []struct {
+		name     string
+		status   *wf.WorkflowStatus
+		want     map[string]Output
+		wantErrs []string
+	}{
+		{
+			name:   "nothing reported",
+			status: &wf.WorkflowStatus{},
+			want:   map[string]Output{},
+		},
+		{
+			name: "workflow outputs are typed by declaration",
+			status: &wf.WorkflowStatus{
+				Outputs: outputs("url", "https://env.example.com", "db_password", "env-db-credentials", "region", "eu-west-1"),
+			},
+			want: map[string]Output{
+				"url":         {Type: blueprint.OutputURL, Value: "https://env.example.com", Description: "Ingress URL"},
+				"db_password": {Type: blueprint.OutputSecret, Value: "env-db-credentials", Reference: true},
+				"region":      {Type: blueprint.OutputString, Value: "eu-west-1"},
+			},
+		},
+		{
+			name: "only declared node outputs",
+			status: &wf.WorkflowStatus{
+				Nodes: wf.Nodes{
+					"env-1": {Outputs: outputs("db_host", "db.internal", "scratch", "tmp")},
+				},
+			},
+			want: map[string]Output{
+				"db_host": {Type: blueprint.OutputString, Value: "db.internal"},
+			},
+		},
+		{
+			name: "workflow outputs win over node outputs",
+			status: &wf.WorkflowStatus{
+				Outputs: outputs("db_host", "from-workflow"),
+				Nodes: wf.Nodes{
+					"env-1": {Outputs: outputs("db_host", "from-node")},
+				},
+			},
+			want: map[string]Output{
+				"db_host": {Type: blueprint.OutputString, Value: "from-workflow"},
+			},
+		},
+		{
+			name: "declared terraform outputs",
+			status: &wf.WorkflowStatus{
+				Nodes: wf.Nodes{
+					"env-1": {Outputs: outputs(OutputTerraform, `{
+						"db_host": {"value": "db.internal", "sensitive": false},
+						"url": {"value": "https://tf.example.com", "sensitive": false},
+						"db_password": {"value": "hunter2", "sensitive": true},
+						"instance_count": {"value": 3, "sensitive": false}
+					}`)},
+				},
+			},
+			want: map[string]Output{
+				"db_host": {Type: blueprint.OutputString, Value: "db.internal"},
+				"url":     {Type: blueprint.OutputURL, Value: "https://tf.example.com", Description: "Ingress URL"},
+			},
+		},
+		{
+			name: "secret output that is not a secret name",
+			status: &wf.WorkflowStatus{
+				Outputs: outputs("db_password", "Hunter2 !", "db_host", "db.internal"),
+			},
+			want: map[string]Output{
+				"db_host": {Type: blueprint.OutputString, Value: "db.internal"},
+			},
+			wantErrs: []string{"db_password: secret outputs must name a Secret"},
+		},
+		{
+			name: "malformed terraform outputs",
+			status: &wf.WorkflowStatus{
+				Outputs: outputs(OutputTerraform, "not json", "db_host", "db.internal"),
+			},
+			want: map[string]Output{
+				"db_host": {Type: blueprint.OutputString, Value: "db.internal"},
+			},
+			wantErrs: []string{"decode " + OutputTerraform},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{DeclaredOutputs: declared}
+
+			got, err := CaptureOutputs(env, tt.status)
+
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Fatalf("CaptureOutputs() err = %v", err)
+			}
+			for _, want := range tt.wantErrs {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Fatalf("CaptureOutputs() err = %v, want %q", err, want)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("CaptureOutputs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---- Helpers ----
+
+// outputs builds workflow outputs from name/value pairs.
+func outputs(pairs ...string) *wf.Outputs {
+
+	out := &wf.Outputs{}
+
+	for i := 0; i < len(pairs); i += 2 {
+		out.Parameters = append(out.Parameters, wf.Parameter{
+			Name:  pairs[i],
+			Value: wf.AnyStringPtr(pairs[i+1]),
+		})
+	}
+
+	return out
+}
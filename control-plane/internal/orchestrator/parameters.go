@@ -0,0 +1,131 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+)
+
+//
+// Template Parameters
+//
+// The parameters the orchestrators themselves submit. Templates of
+// each purpose must accept them; the contract check (see package
+// contract) compares them with what a template declares.
+//
+
+const (
+	ParamEnvName   = "env_name"
+	ParamService   = "service"
+	ParamExpiresAt = "expires_at"
+	ParamRevision  = "revision"
+	ParamImage     = "image"
+	ParamNamespace = "namespace"
+)
+
+// Parameters of the templates of Terraform-backed blueprints (see
+// TerraformState). tf_vars is a JSON object of Terraform variables.
+const (
+	ParamTFModule        = "tf_module"
+	ParamTFModuleVersion = "tf_module_version"
+	ParamTFStateKey      = "tf_state_key"
+	ParamTFVars          = "tf_vars"
+)
+
+// SubmittedParameters returns the platform-supplied parameters for a
+// template purpose.
+//
+// CI runs add the caller's parameters on top of these, and revision
+// only when one is given. Deployments add the caller's parameters too.
+func SubmittedParameters(purpose catalog.Purpose) []string {
+
+	switch purpose {
+	case catalog.PurposeEnvCreate:
+		return []string{ParamEnvName, ParamService, ParamExpiresAt}
+	case catalog.PurposeEnvTTL:
+		return []string{ParamEnvName, ParamExpiresAt}
+	case catalog.PurposeEnvDestroy:
+		return []string{ParamEnvName}
+	case catalog.PurposeCI:
+		return []string{ParamService, ParamRevision}
+	case catalog.PurposeDeploy:
+		return []string{ParamService, ParamImage, ParamRevision, ParamNamespace}
+	default:
+		return nil
+	}
+}
+
+// BlueprintParameters returns the platform-supplied parameters for
+// the blueprint's template of a purpose.
+func BlueprintParameters(
+	bp blueprint.Blueprint,
+	purpose catalog.Purpose,
+) []string {
+
+	params := SubmittedParameters(purpose)
+
+	if bp.Terraform != nil && purpose != catalog.PurposeCI {
+		params = append(params, ParamTFModule, ParamTFModuleVersion, ParamTFStateKey, ParamTFVars)
+	}
+
+	return params
+}
+
+// validateUserParameters checks user-supplied parameters against the
+// blueprint's and the template's allowlists. Platform-supplied
+// parameters can never be set by users, even when a template lists
+// them.
+func validateUserParameters(
+	bp blueprint.Blueprint,
+	template catalog.Template,
+	params map[string]string,
+) error {
+
+	if err := rejectPlatformParameters(template, params); err != nil {
+		return err
+	}
+
+	var denied []string
+	for name := range params {
+		if !bp.AllowsParameter(name) {
+			denied = append(denied, name)
+		}
+	}
+
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		return fmt.Errorf(
+			"%w: not allowed by blueprint %s: %s",
+			catalog.ErrInvalidParameters,
+			bp.Name,
+			strings.Join(denied, ", "),
+		)
+	}
+
+	// Terraform variables are not template parameters; the blueprint
+	// allowlist is all there is.
+	if bp.Terraform != nil {
+		return nil
+	}
+
+	return template.ValidateUserParameters(params)
+}
+
+// rejectPlatformParameters fails when params sets a parameter the
+// platform submits for the template's purpose.
+func rejectPlatformParameters(
+	template catalog.Template,
+	params map[string]string,
+) error {
+
+	for _, name := range SubmittedParameters(template.Purpose) {
+		if _, ok := params[name]; ok {
+			return fmt.Errorf("%w: %s is set by the platform", catalog.ErrInvalidParameters, name)
+		}
+	}
+
+	return nil
+}
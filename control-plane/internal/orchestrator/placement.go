@@ -0,0 +1,109 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+// PlacementRule places matching environments on a cluster.
+//
+// Service is a path.Match pattern ("load-*"); Team must match exactly.
+// Empty fields match anything. Rules are evaluated in order.
+type PlacementRule struct {
+	Service string
+	Team    string
+	Cluster string
+}
+
+// ClusterPlacer decides which cluster hosts an environment.
+//
+// Resolution order:
+//  1. EnvironmentSpec.Cluster (explicit request or service default)
+//  2. the first matching PlacementRule
+//  3. the default cluster
+type ClusterPlacer struct {
+	defaultCluster string
+	known          map[string]bool
+	rules          []PlacementRule
+}
+
+// NewClusterPlacer fails if a rule targets a cluster that is not
+// configured, so misplacement is caught at startup.
+func NewClusterPlacer(
+	defaultCluster string,
+	clusters []string,
+	rules []PlacementRule,
+) (*ClusterPlacer, error) {
+
+	known := make(map[string]bool, len(clusters))
+	for _, c := range clusters {
+		known[c] = true
+	}
+
+	for _, r := range rules {
+		if !known[r.Cluster] {
+			return nil, fmt.Errorf("placement rule: %w: %s", executor.ErrUnknownCluster, r.Cluster)
+		}
+	}
+
+	return &ClusterPlacer{
+		defaultCluster: defaultCluster,
+		known:          known,
+		rules:          rules,
+	}, nil
+}
+
+// Place returns the cluster for spec, or an error wrapping
+// executor.ErrUnknownCluster if it names a cluster that is not
+// configured.
+func (p *ClusterPlacer) Place(spec EnvironmentSpec) (string, error) {
+
+	cluster := spec.Cluster
+
+	if cluster == "" {
+		cluster = p.defaultCluster
+
+		for _, r := range p.rules {
+			if r.matches(spec) {
+				cluster = r.Cluster
+				break
+			}
+		}
+	}
+
+	if !p.known[cluster] {
+		return "", fmt.Errorf("%w: %s", executor.ErrUnknownCluster, cluster)
+	}
+
+	return cluster, nil
+}
+
+// Clusters returns every configured cluster, sorted.
+func (p *ClusterPlacer) Clusters() []string {
+
+	out := make([]string, 0, len(p.known))
+	for c := range p.known {
+		out = append(out, c)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+func (r PlacementRule) matches(spec EnvironmentSpec) bool {
+
+	if r.Team != "" && r.Team != spec.Team {
+		return false
+	}
+
+	if r.Service != "" {
+		if ok, _ := path.Match(r.Service, spec.Service); !ok {
+			return false
+		}
+	}
+
+	return true
+}
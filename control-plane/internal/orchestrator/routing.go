@@ -0,0 +1,99 @@
+package orchestrator
+
+import "sort"
+
+// NamespaceRouter decides which execution namespace a workflow runs in.
+//
+// Resolution order:
+//  1. a route for the service
+//  2. the owning team's registered default namespace
+//  3. a configured route for the owning team
+//  4. the default namespace
+//
+// Routing to a namespace also routes to that namespace's service
+// accounts, RBAC and ResourceQuotas; the templates referenced by the
+// catalog must be installed there too.
+type NamespaceRouter struct {
+	defaultNamespace string
+	services         map[string]string
+	teams            map[string]string
+	registered       TeamNamespaces
+}
+
+// TeamNamespaces looks up the default namespaces of registered teams.
+type TeamNamespaces interface {
+	TeamNamespace(team string) (string, bool)
+	RegisteredNamespaces() []string
+}
+
+func NewNamespaceRouter(
+	defaultNamespace string,
+	services map[string]string,
+	teams map[string]string,
+) *NamespaceRouter {
+	return &NamespaceRouter{
+		defaultNamespace: defaultNamespace,
+		services:         services,
+		teams:            teams,
+	}
+}
+
+// WithTeams consults registered teams before configured team routes.
+func (r *NamespaceRouter) WithTeams(teams TeamNamespaces) *NamespaceRouter {
+	r.registered = teams
+	return r
+}
+
+// Resolve returns the execution namespace for a service.
+func (r *NamespaceRouter) Resolve(service string, team string) string {
+
+	if ns, ok := r.services[service]; ok {
+		return ns
+	}
+
+	if r.registered != nil && team != "" {
+		if ns, ok := r.registered.TeamNamespace(team); ok {
+			return ns
+		}
+	}
+
+	if ns, ok := r.teams[team]; ok && team != "" {
+		return ns
+	}
+
+	return r.defaultNamespace
+}
+
+// Default returns the namespace used when no route matches.
+func (r *NamespaceRouter) Default() string {
+	return r.defaultNamespace
+}
+
+// Namespaces returns every namespace a workflow may be routed to,
+// sorted: the default, service and team routes, and registered teams'
+// namespaces.
+func (r *NamespaceRouter) Namespaces() []string {
+
+	seen := map[string]bool{r.defaultNamespace: true}
+	for _, ns := range r.services {
+		seen[ns] = true
+	}
+	for _, ns := range r.teams {
+		seen[ns] = true
+	}
+	if r.registered != nil {
+		for _, ns := range r.registered.RegisteredNamespaces() {
+			seen[ns] = true
+		}
+	}
+
+	out := make([]string, 0, len(seen))
+	for ns := range seen {
+		if ns != "" {
+			out = append(out, ns)
+		}
+	}
+	sort.Strings(out)
+
+	return out
+}
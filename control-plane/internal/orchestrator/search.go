@@ -0,0 +1,91 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+//
+// ----- DOMAIN TYPES -----
+//
+
+// ErrInvalidQuery wraps every rejected search query.
+var ErrInvalidQuery = errors.New("invalid workflow query")
+
+// WorkflowQuery filters workflows submitted by the control plane.
+//
+// Every string filter matches a platform label exactly; empty filters
+// match anything. Phases match any of the listed phases.
+//
+// Active keeps only workflows that have not completed, including ones
+// the controller has not picked up yet. It is pushed into the label
+// selector, so completed history is not listed at all where the
+// execution plane sets Argo's completed label.
+//
+// Namespace defaults to the namespace routed for Service/Team, or to
+// every routed namespace without either, and Cluster to every
+// configured cluster: one query may span several targets.
+type WorkflowQuery struct {
+	Service     string
+	Team        string
+	Environment string
+	Type        string
+	Trigger     string
+	Template    string
+	Phases      []string
+	Active      bool
+
+	Cluster   string
+	Namespace string
+
+	SubmittedAfter  time.Time
+	SubmittedBefore time.Time
+
+	Limit    int64
+	Continue string
+}
+
+// WorkflowSummary is the search view of one workflow.
+//
+// Label-derived fields hold the caller's original values, even where
+// the label itself had to be sanitized.
+type WorkflowSummary struct {
+	Name        string
+	Cluster     string
+	Namespace   string
+	UID         string
+	Type        string
+	Service     string
+	Environment string
+	Trigger     string
+	Template    string
+	Phase       string
+	Message     string
+	SubmittedAt time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
+
+// WorkflowPage is one page of search results.
+//
+// Phase and time filters are applied after paging, so a page may hold
+// fewer than Limit items, or none, while Continue is still set.
+// Callers should follow Continue until it is empty.
+type WorkflowPage struct {
+	Items    []WorkflowSummary
+	Continue string
+}
+
+//
+// ----- SEARCH CONTRACT -----
+//
+
+// WorkflowSearch queries workflows by their platform labels.
+//
+// It is read-only and stateless: results come straight from the
+// execution plane, so they include workflows submitted by other
+// replicas and by earlier processes.
+type WorkflowSearch interface {
+	Search(ctx context.Context, q WorkflowQuery) (*WorkflowPage, error)
+}
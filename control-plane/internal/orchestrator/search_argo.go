@@ -0,0 +1,334 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow"
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+// Page sizes for workflow search.
+const (
+	DefaultSearchLimit int64 = 50
+	MaxSearchLimit     int64 = 500
+)
+
+// labelCompleted is set to "true" by the Argo controller once a
+// workflow finishes.
+const labelCompleted = workflow.WorkflowFullName + "/completed"
+
+var searchablePhases = map[wf.WorkflowPhase]bool{
+	wf.WorkflowPending:   true,
+	wf.WorkflowRunning:   true,
+	wf.WorkflowSucceeded: true,
+	wf.WorkflowFailed:    true,
+	wf.WorkflowError:     true,
+}
+
+type ArgoWorkflowSearch struct {
+	workflows executor.WorkflowLister
+	router    *NamespaceRouter
+	placer    *ClusterPlacer
+}
+
+func NewArgoWorkflowSearch(
+	workflows executor.WorkflowLister,
+	router *NamespaceRouter,
+	placer *ClusterPlacer,
+) *ArgoWorkflowSearch {
+	return &ArgoWorkflowSearch{
+		workflows: workflows,
+		router:    router,
+		placer:    placer,
+	}
+}
+
+// Search lists one page of platform workflows matching q.
+//
+// Targets (cluster and namespace pairs) are listed in order, each
+// followed to its end before the next; the continue token records the
+// target and its own continue token.
+//
+// Label filters are pushed down to the execution plane as a selector;
+// phase and submission time are filtered here.
+func (s *ArgoWorkflowSearch) Search(
+	ctx context.Context,
+	q WorkflowQuery,
+) (*WorkflowPage, error) {
+
+	if err := validateQuery(&q); err != nil {
+		return nil, err
+	}
+
+	targets := s.targets(q)
+
+	start, cont, err := decodeCursor(q.Continue, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &WorkflowPage{
+		Items: make([]WorkflowSummary, 0),
+	}
+
+	var listed int64
+
+	for i := start; i < len(targets); i++ {
+		target := targets[i]
+
+		list, err := s.workflows.ListWorkflows(
+			ctx,
+			target,
+			executor.ListOptions{
+				LabelSelector: querySelector(q),
+				Limit:         q.Limit - listed,
+				Continue:      cont,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("search workflows in %s/%s: %w", target.Cluster, target.Namespace, err)
+		}
+		cont = ""
+
+		listed += int64(len(list.Items))
+
+		for j := range list.Items {
+			w := &list.Items[j]
+			if !matchesQuery(w, q) {
+				continue
+			}
+			page.Items = append(page.Items, toWorkflowSummary(w, target.Cluster))
+		}
+
+		if list.Continue != "" {
+			page.Continue = encodeCursor(target, list.Continue)
+			break
+		}
+
+		if listed >= q.Limit {
+			if i+1 < len(targets) {
+				page.Continue = encodeCursor(targets[i+1], "")
+			}
+			break
+		}
+	}
+
+	return page, nil
+}
+
+// targets are the clusters and namespaces q spans, in a stable order.
+func (s *ArgoWorkflowSearch) targets(q WorkflowQuery) []executor.Target {
+
+	clusters := []string{q.Cluster}
+	if q.Cluster == "" {
+		clusters = s.placer.Clusters()
+	}
+
+	namespaces := []string{q.Namespace}
+	switch {
+	case q.Namespace != "":
+	case q.Service != "" || q.Team != "":
+		namespaces = []string{s.router.Resolve(q.Service, q.Team)}
+	default:
+		namespaces = s.router.Namespaces()
+	}
+
+	out := make([]executor.Target, 0, len(clusters)*len(namespaces))
+	for _, c := range clusters {
+		for _, ns := range namespaces {
+			out = append(out, executor.Target{Cluster: c, Namespace: ns})
+		}
+	}
+
+	return out
+}
+
+//
+// ---- Helpers ----
+//
+
+func validateQuery(q *WorkflowQuery) error {
+
+	var problems []string
+
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultSearchLimit
+	case q.Limit < 0 || q.Limit > MaxSearchLimit:
+		problems = append(problems, fmt.Sprintf("limit must be between 1 and %d", MaxSearchLimit))
+	}
+
+	for _, p := range q.Phases {
+		if !searchablePhases[wf.WorkflowPhase(p)] {
+			problems = append(problems, fmt.Sprintf("unknown phase %q", p))
+		}
+	}
+
+	if !q.SubmittedAfter.IsZero() && !q.SubmittedBefore.IsZero() &&
+		!q.SubmittedAfter.Before(q.SubmittedBefore) {
+		problems = append(problems, "submitted_after must be before submitted_before")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidQuery, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// querySelector builds the label selector for the label filters.
+//
+// Values go through executor.LabelValue so that they match what the
+// executor wrote, however the original was sanitized.
+func querySelector(q WorkflowQuery) string {
+
+	set := k8slabels.Set{
+		LabelControlPlane: "true",
+	}
+
+	for key, value := range map[string]string{
+		LabelService:          q.Service,
+		LabelEnvironment:      q.Environment,
+		LabelWorkflowType:     q.Type,
+		LabelTrigger:          q.Trigger,
+		LabelWorkflowTemplate: q.Template,
+	} {
+		if value != "" {
+			set[key] = executor.LabelValue(value)
+		}
+	}
+
+	selector := k8slabels.SelectorFromValidatedSet(set)
+
+	if q.Active {
+		// Argo labels workflows completed=true once they finish and
+		// not before, so this also matches workflows not yet started.
+		notCompleted, err := k8slabels.NewRequirement(labelCompleted, selection.NotEquals, []string{"true"})
+		if err == nil {
+			selector = selector.Add(*notCompleted)
+		}
+	}
+
+	return selector.String()
+}
+
+func matchesQuery(w *wf.Workflow, q WorkflowQuery) bool {
+
+	if q.Active && w.Status.Phase.Completed() {
+		return false
+	}
+
+	if len(q.Phases) > 0 {
+		matched := false
+		for _, p := range q.Phases {
+			if string(w.Status.Phase) == p {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	submitted := w.CreationTimestamp.Time
+
+	if !q.SubmittedAfter.IsZero() && submitted.Before(q.SubmittedAfter) {
+		return false
+	}
+	if !q.SubmittedBefore.IsZero() && !submitted.Before(q.SubmittedBefore) {
+		return false
+	}
+
+	return true
+}
+
+func toWorkflowSummary(w *wf.Workflow, cluster string) WorkflowSummary {
+
+	label := func(key string) string {
+		if original, ok := w.Annotations[executor.AnnotationOriginalLabelPrefix+key]; ok {
+			return original
+		}
+		return w.Labels[key]
+	}
+
+	summary := WorkflowSummary{
+		Name:        w.Name,
+		Cluster:     cluster,
+		Namespace:   w.Namespace,
+		UID:         string(w.UID),
+		Type:        label(LabelWorkflowType),
+		Service:     label(LabelService),
+		Environment: label(LabelEnvironment),
+		Trigger:     label(LabelTrigger),
+		Template:    label(LabelWorkflowTemplate),
+		Phase:       string(w.Status.Phase),
+		Message:     w.Status.Message,
+		SubmittedAt: w.CreationTimestamp.Time,
+	}
+
+	summary.StartedAt = timePtr(w.Status.StartedAt.Time)
+	summary.FinishedAt = timePtr(w.Status.FinishedAt.Time)
+
+	return summary
+}
+
+// cursor is the decoded continue token of a search.
+type cursor struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Continue  string `json:"continue,omitempty"`
+}
+
+func encodeCursor(target executor.Target, cont string) string {
+
+	raw, _ := json.Marshal(cursor{
+		Cluster:   target.Cluster,
+		Namespace: target.Namespace,
+		Continue:  cont,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor returns the index of the target to resume from and its
+// continue token. An empty token starts at the first target.
+func decodeCursor(token string, targets []executor.Target) (int, string, error) {
+
+	if token == "" {
+		return 0, "", nil
+	}
+
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: malformed continue token", ErrInvalidQuery)
+	}
+
+	for i, t := range targets {
+		if t.Cluster == c.Cluster && t.Namespace == c.Namespace {
+			return i, c.Continue, nil
+		}
+	}
+
+	return 0, "", fmt.Errorf("%w: continue token does not match the query", ErrInvalidQuery)
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,49 @@
+package orchestrator
+
+import (
+	"testing"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+)
+
+func TestActiveQuery(t *testing.T) {
+
+	selector, err := k8slabels.Parse(querySelector(WorkflowQuery{Service: "payments", Active: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		completed string
+		phase     wf.WorkflowPhase
+		want      bool
+	}{
+		{"not yet picked up", "", "", true},
+		{"running", "false", wf.WorkflowRunning, true},
+		{"completed", "true", wf.WorkflowSucceeded, false},
+		{"completed without label", "", wf.WorkflowFailed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &wf.Workflow{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					LabelControlPlane: "true",
+					LabelService:      "payments",
+				}},
+				Status: wf.WorkflowStatus{Phase: tt.phase},
+			}
+			if tt.completed != "" {
+				w.Labels[labelCompleted] = tt.completed
+			}
+
+			got := selector.Matches(k8slabels.Set(w.Labels)) && matchesQuery(w, WorkflowQuery{Active: true})
+			if got != tt.want {
+				t.Fatalf("active = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
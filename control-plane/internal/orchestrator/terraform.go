@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+)
+
+// OutputTerraform is the output parameter in which Terraform create
+// templates report `terraform output -json`, sensitive values removed.
+const OutputTerraform = "terraform_outputs"
+
+// TerraformState records what a Terraform-backed environment was
+// applied with. Destroy and TTL cleanup reuse it rather than the
+// blueprint, so a module upgrade never changes how an existing
+// environment is torn down.
+type TerraformState struct {
+	Module    string            `json:"module"`
+	Version   string            `json:"version"`
+	StateKey  string            `json:"state_key"`
+	Variables map[string]string `json:"variables"`
+}
+
+// newTerraformState pins the blueprint's module for one environment.
+// The state key is unique to this environment, so recreating a
+// destroyed environment of the same name never reuses its state.
+// Variables are the user's parameters plus the platform's own, which
+// validateUserParameters keeps users from overriding.
+func newTerraformState(
+	tf *blueprint.Terraform,
+	spec EnvironmentSpec,
+	expiresAt string,
+) *TerraformState {
+
+	vars := make(map[string]string, len(spec.Parameters)+3)
+	for k, v := range spec.Parameters {
+		vars[k] = v
+	}
+
+	vars[ParamEnvName] = spec.Name
+	vars[ParamService] = spec.Service
+	vars[ParamExpiresAt] = expiresAt
+
+	return &TerraformState{
+		Module:    tf.Module,
+		Version:   tf.Version,
+		StateKey:  path.Join(tf.StatePrefix, spec.Cluster, spec.Name, uuid.NewString()+".tfstate"),
+		Variables: vars,
+	}
+}
+
+// addParameters sets the Terraform template parameters.
+func (s *TerraformState) addParameters(params map[string]string) error {
+
+	vars, err := json.Marshal(s.Variables)
+	if err != nil {
+		return fmt.Errorf("encode terraform variables: %w", err)
+	}
+
+	params[ParamTFModule] = s.Module
+	params[ParamTFModuleVersion] = s.Version
+	params[ParamTFStateKey] = s.StateKey
+	params[ParamTFVars] = string(vars)
+
+	return nil
+}
+
+// terraformOutputs decodes the OutputTerraform parameter. String
+// outputs are kept as they are; other types are kept as JSON.
+// Sensitive outputs are skipped: their values must reach consumers
+// through a Secret, never the control plane.
+func terraformOutputs(raw string) (map[string]string, error) {
+
+	var decoded map[string]struct {
+		Value     json.RawMessage `json:"value"`
+		Sensitive bool            `json:"sensitive"`
+	}
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", OutputTerraform, err)
+	}
+
+	outputs := make(map[string]string, len(decoded))
+	for name, o := range decoded {
+		if o.Sensitive {
+			continue
+		}
+
+		var s string
+		if err := json.Unmarshal(o.Value, &s); err == nil {
+			outputs[name] = s
+			continue
+		}
+		outputs[name] = strings.TrimSpace(string(o.Value))
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,204 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidSpec wraps every validation failure.
+var ErrInvalidSpec = errors.New("invalid pipeline spec")
+
+// Spec is a declarative pipeline: a DAG of stages, each running
+// a group of steps.
+//
+// Example:
+//
+//	{
+//	  "name": "build-and-test",
+//	  "stages": [
+//	    {"name": "build", "image": "node:20",
+//	     "steps": [{"name": "compile", "command": ["npm", "run", "build"]}]},
+//	    {"name": "test", "image": "node:20", "depends_on": ["build"],
+//	     "parallelism": 2,
+//	     "steps": [
+//	       {"name": "unit", "command": ["npm", "test"]},
+//	       {"name": "lint", "command": ["npm", "run", "lint"]}
+//	     ]}
+//	  ]
+//	}
+type Spec struct {
+	Name   string  `json:"name"`
+	Stages []Stage `json:"stages"`
+}
+
+// Stage is a node in the pipeline DAG.
+//
+// Steps inside a stage run in parallel, bounded by Parallelism
+// (0 means unbounded). Image is the default for steps that do not
+// set their own.
+type Stage struct {
+	Name        string   `json:"name"`
+	Image       string   `json:"image"`
+	DependsOn   []string `json:"depends_on"`
+	Parallelism int64    `json:"parallelism"`
+	Steps       []Step   `json:"steps"`
+}
+
+// Step is a single container invocation.
+type Step struct {
+	Name    string            `json:"name"`
+	Image   string            `json:"image"`
+	Command []string          `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+}
+
+// Validate checks names, references and the stage DAG.
+//
+// All problems are reported together.
+func (s *Spec) Validate() error {
+
+	var problems []string
+
+	if !namePattern.MatchString(s.Name) {
+		problems = append(problems, fmt.Sprintf("name: %q is not a valid name", s.Name))
+	}
+
+	if len(s.Stages) == 0 {
+		problems = append(problems, "stages: at least one stage is required")
+	}
+
+	stages := make(map[string]bool, len(s.Stages))
+
+	for i, stage := range s.Stages {
+		field := fmt.Sprintf("stages[%d]", i)
+
+		if !namePattern.MatchString(stage.Name) {
+			problems = append(problems, fmt.Sprintf("%s.name: %q is not a valid name", field, stage.Name))
+		}
+		if stages[stage.Name] {
+			problems = append(problems, fmt.Sprintf("%s.name: duplicate stage %q", field, stage.Name))
+		}
+		stages[stage.Name] = true
+
+		if stage.Parallelism < 0 {
+			problems = append(problems, fmt.Sprintf("%s.parallelism: must not be negative", field))
+		}
+
+		if len(stage.Steps) == 0 {
+			problems = append(problems, fmt.Sprintf("%s.steps: at least one step is required", field))
+		}
+
+		steps := make(map[string]bool, len(stage.Steps))
+
+		for j, step := range stage.Steps {
+			stepField := fmt.Sprintf("%s.steps[%d]", field, j)
+
+			if !namePattern.MatchString(step.Name) {
+				problems = append(problems, fmt.Sprintf("%s.name: %q is not a valid name", stepField, step.Name))
+			}
+			if steps[step.Name] {
+				problems = append(problems, fmt.Sprintf("%s.name: duplicate step %q", stepField, step.Name))
+			}
+			steps[step.Name] = true
+
+			if step.Image == "" && stage.Image == "" {
+				problems = append(problems, fmt.Sprintf("%s.image: required when the stage has no image", stepField))
+			}
+			if len(step.Command) == 0 {
+				problems = append(problems, fmt.Sprintf("%s.command: required", stepField))
+			}
+		}
+	}
+
+	for i, stage := range s.Stages {
+		for _, dep := range stage.DependsOn {
+			switch {
+			case dep == stage.Name:
+				problems = append(problems, fmt.Sprintf("stages[%d].depends_on: %q depends on itself", i, dep))
+			case !stages[dep]:
+				problems = append(problems, fmt.Sprintf("stages[%d].depends_on: unknown stage %q", i, dep))
+			}
+		}
+	}
+
+	if cycle := s.findCycle(); cycle != nil {
+		problems = append(problems, fmt.Sprintf("stages: dependency cycle %s", strings.Join(cycle, " -> ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidSpec, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// findCycle returns one dependency cycle, closed on its first stage,
+// or nil if the stage graph is acyclic.
+//
+// Self-dependencies and unknown references are reported separately
+// by Validate and ignored here.
+func (s *Spec) findCycle() []string {
+
+	deps := make(map[string][]string, len(s.Stages))
+	names := make([]string, 0, len(s.Stages))
+
+	for _, stage := range s.Stages {
+		names = append(names, stage.Name)
+		deps[stage.Name] = stage.DependsOn
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make(map[string]int, len(names))
+	var path []string
+	var cycle []string
+
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dep := range deps[name] {
+			if _, known := deps[dep]; !known || dep == name {
+				continue
+			}
+
+			switch state[dep] {
+			case visiting:
+				for i, n := range path {
+					if n == dep {
+						cycle = append(append([]string{}, path[i:]...), dep)
+						return true
+					}
+				}
+			case unvisited:
+				if visit(dep) {
+					return true
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = done
+		return false
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited && visit(name) {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
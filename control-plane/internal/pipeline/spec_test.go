@@ -0,0 +1,134 @@
+package pipeline
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFindCycle(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		stages map[string][]string
+		want   string
+	}{
+		{
+			name:   "single stage",
+			stages: map[string][]string{"build": nil},
+		},
+		{
+			name: "chain",
+			stages: map[string][]string{
+				"build":  nil,
+				"test":   {"build"},
+				"deploy": {"test"},
+			},
+		},
+		{
+			name: "diamond",
+			stages: map[string][]string{
+				"build": nil,
+				"unit":  {"build"},
+				"lint":  {"build"},
+				"ship":  {"unit", "lint"},
+			},
+		},
+		{
+			name: "two stages",
+			stages: map[string][]string{
+				"a": {"b"},
+				"b": {"a"},
+			},
+			want: "a -> b -> a",
+		},
+		{
+			name: "cycle behind an acyclic prefix",
+			stages: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"d"},
+				"d": {"b"},
+			},
+			want: "b -> c -> d -> b",
+		},
+		{
+			name: "self and unknown dependencies are ignored",
+			stages: map[string][]string{
+				"a": {"a", "missing"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(specOf(tt.stages).findCycle(), " -> ")
+			if got != tt.want {
+				t.Fatalf("findCycle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		stages  map[string][]string
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			stages: map[string][]string{"build": nil, "test": {"build"}},
+		},
+		{
+			name:    "cycle",
+			stages:  map[string][]string{"a": {"b"}, "b": {"a"}},
+			wantErr: "dependency cycle a -> b -> a",
+		},
+		{
+			name:    "self dependency",
+			stages:  map[string][]string{"a": {"a"}},
+			wantErr: `"a" depends on itself`,
+		},
+		{
+			name:    "unknown dependency",
+			stages:  map[string][]string{"a": {"b"}},
+			wantErr: `unknown stage "b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := specOf(tt.stages).Validate()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidSpec) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// specOf builds a valid spec with the given stage dependencies.
+func specOf(stages map[string][]string) *Spec {
+
+	s := &Spec{Name: "pipeline"}
+
+	for name, deps := range stages {
+		s.Stages = append(s.Stages, Stage{
+			Name:      name,
+			Image:     "alpine:3",
+			DependsOn: deps,
+			Steps:     []Step{{Name: "run", Command: []string{"true"}}},
+		})
+	}
+
+	return s
+}
@@ -0,0 +1,34 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentType is the media type for RFC 9457 problem details.
+const ContentType = "application/problem+json"
+
+// Details is an RFC 9457 problem details object.
+//
+// Type is left as "about:blank": Title is then the HTTP status text
+// and Detail carries the specific reason.
+type Details struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Write sends a problem+json response.
+//
+// Headers (WWW-Authenticate, Retry-After...) must be set before calling.
+func Write(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
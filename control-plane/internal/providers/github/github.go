@@ -1,13 +1,43 @@
 package github
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
-// Provider is a placeholder GitHub implementation of RepositoryProvider.
-// Real logic will be added in Phase 4.
-type Provider struct{}
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/providers"
+)
 
-func New() *Provider {
-	return &Provider{}
+const defaultAPIURL = "https://api.github.com"
+
+// maxFileSize bounds how much of a repository file is read.
+// Platform files are small; anything larger is a mistake.
+const maxFileSize = 1 << 20
+
+// Provider is a GitHub implementation of RepositoryProvider.
+//
+// Only reading files, branches and pull requests is implemented so
+// far. Repository validation and type detection arrive in Phase 4.
+type Provider struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// New creates a GitHub provider.
+//
+// token may be empty for public repositories.
+func New(token string) *Provider {
+	return &Provider{
+		apiURL: defaultAPIURL,
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
 }
 
 func (p *Provider) ValidateRepo(repoURL string) error {
@@ -17,3 +47,235 @@ func (p *Provider) ValidateRepo(repoURL string) error {
 func (p *Provider) DetectProjectType(repoURL string) (string, error) {
 	return "", fmt.Errorf("github provider not implemented")
 }
+
+// FetchFile reads a file through the GitHub contents API.
+func (p *Provider) FetchFile(
+	ctx context.Context,
+	repoURL string,
+	revision string,
+	path string,
+) ([]byte, error) {
+
+	owner, repo, err := parseRepoURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/repos/%s/%s/contents/%s",
+		p.apiURL,
+		owner,
+		repo,
+		strings.TrimPrefix(path, "/"),
+	)
+	if revision != "" {
+		endpoint += "?ref=" + url.QueryEscape(revision)
+	}
+
+	resp, err := p.get(ctx, endpoint, "application/vnd.github.raw+json")
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s from %s/%s: %w", path, owner, repo, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, providers.ErrFileNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf(
+			"fetch %s from %s/%s: unexpected status %d",
+			path,
+			owner,
+			repo,
+			resp.StatusCode,
+		)
+	}
+
+	// Read one byte past the limit, so that an oversized file is
+	// reported rather than parsed cut off.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s from %s/%s: %w", path, owner, repo, err)
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf(
+			"%w: %s in %s/%s exceeds %d bytes",
+			providers.ErrFileTooLarge,
+			path,
+			owner,
+			repo,
+			maxFileSize,
+		)
+	}
+
+	return data, nil
+}
+
+// BranchContains compares revision with branch: branch contains
+// revision when it is identical to or ahead of it.
+func (p *Provider) BranchContains(
+	ctx context.Context,
+	repoURL string,
+	branch string,
+	revision string,
+) (bool, error) {
+
+	owner, repo, err := parseRepoURL(repoURL)
+	if err != nil {
+		return false, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/repos/%s/%s/compare/%s...%s",
+		p.apiURL,
+		owner,
+		repo,
+		escapeRef(revision),
+		escapeRef(branch),
+	)
+
+	var compare struct {
+		Status string `json:"status"`
+	}
+
+	found, err := p.getJSON(ctx, endpoint, &compare)
+	if err != nil {
+		return false, fmt.Errorf("compare %s with %s in %s/%s: %w", revision, branch, owner, repo, err)
+	}
+
+	return found && (compare.Status == "identical" || compare.Status == "ahead"), nil
+}
+
+// PullRequest reads a pull request through the GitHub pulls API.
+func (p *Provider) PullRequest(
+	ctx context.Context,
+	repoURL string,
+	number int,
+) (*providers.PullRequest, error) {
+
+	owner, repo, err := parseRepoURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", p.apiURL, owner, repo, number)
+
+	var pr struct {
+		State string `json:"state"`
+		Base  struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+		Head struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+
+	found, err := p.getJSON(ctx, endpoint, &pr)
+	if err != nil {
+		return nil, fmt.Errorf("fetch pull request %d of %s/%s: %w", number, owner, repo, err)
+	}
+	if !found {
+		return nil, providers.ErrPullRequestNotFound
+	}
+
+	return &providers.PullRequest{
+		Number:  number,
+		Open:    pr.State == "open",
+		Base:    pr.Base.Ref,
+		Head:    pr.Head.Ref,
+		HeadSHA: pr.Head.SHA,
+	}, nil
+}
+
+// Ping checks that the GitHub API is reachable and, when a token is
+// configured, that it is accepted. The rate limit endpoint does not
+// count against the rate limit.
+func (p *Provider) Ping(ctx context.Context) error {
+
+	resp, err := p.get(ctx, p.apiURL+"/rate_limit", "application/vnd.github+json")
+	if err != nil {
+		return fmt.Errorf("github api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github api: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+//
+// ---- Helpers ----
+//
+
+func (p *Provider) get(ctx context.Context, endpoint string, accept string) (*http.Response, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", accept)
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	return p.client.Do(req)
+}
+
+// getJSON decodes endpoint into v. found is false on 404.
+func (p *Provider) getJSON(ctx context.Context, endpoint string, v interface{}) (found bool, err error) {
+
+	resp, err := p.get(ctx, endpoint, "application/vnd.github+json")
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxFileSize)).Decode(v); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// escapeRef escapes a branch or revision for a URL path, keeping the
+// slashes of branch names such as release/1.2.
+func escapeRef(ref string) string {
+	return strings.ReplaceAll(url.PathEscape(ref), "%2F", "/")
+}
+
+// parseRepoURL accepts https://github.com/<owner>/<repo>[.git]
+// and git@github.com:<owner>/<repo>[.git]. URLs of other hosts are
+// ErrUnsupportedRepository; malformed GitHub URLs are plain errors.
+func parseRepoURL(repoURL string) (string, string, error) {
+
+	trimmed := strings.TrimSuffix(strings.TrimSpace(repoURL), ".git")
+
+	var path string
+	for _, prefix := range []string{"git@github.com:", "https://github.com/", "http://github.com/"} {
+		if rest, ok := strings.CutPrefix(trimmed, prefix); ok {
+			path = rest
+			break
+		}
+	}
+	if path == "" {
+		return "", "", fmt.Errorf("%w: %q is not a github repository", providers.ErrUnsupportedRepository, repoURL)
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("malformed github repository url %q", repoURL)
+	}
+
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,163 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/providers"
+)
+
+func TestParseRepoURL(t *testing.T) {
+
+	tests := []struct {
+		url             string
+		wantOwner       string
+		wantRepo        string
+		wantUnsupported bool
+		wantErr         bool
+	}{
+		{url: "https://github.com/acme/payments", wantOwner: "acme", wantRepo: "payments"},
+		{url: "https://github.com/acme/payments.git", wantOwner: "acme", wantRepo: "payments"},
+		{url: "git@github.com:acme/payments.git", wantOwner: "acme", wantRepo: "payments"},
+		{url: " https://github.com/acme/payments/ ", wantOwner: "acme", wantRepo: "payments"},
+		{url: "https://gitlab.com/acme/payments", wantUnsupported: true},
+		{url: "https://git.example.com/acme/payments", wantUnsupported: true},
+		{url: "git@bitbucket.org:acme/payments.git", wantUnsupported: true},
+		{url: "https://github.com/acme", wantErr: true},
+		{url: "https://github.com/acme/payments/tree/main", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			owner, repo, err := parseRepoURL(tt.url)
+
+			switch {
+			case tt.wantUnsupported:
+				if !errors.Is(err, providers.ErrUnsupportedRepository) {
+					t.Fatalf("err = %v, want ErrUnsupportedRepository", err)
+				}
+			case tt.wantErr:
+				if err == nil || errors.Is(err, providers.ErrUnsupportedRepository) {
+					t.Fatalf("err = %v, want a malformed url error", err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case owner != tt.wantOwner || repo != tt.wantRepo:
+				t.Fatalf("parseRepoURL() = %s/%s, want %s/%s", owner, repo, tt.wantOwner, tt.wantRepo)
+			}
+		})
+	}
+}
+
+func TestFetchFile(t *testing.T) {
+
+	p := newTestProvider(t, map[string]string{
+		"/repos/acme/payments/contents/.platform.yaml": "version: 1\n",
+		"/repos/acme/big/contents/.platform.yaml":      strings.Repeat("#", maxFileSize+1),
+		"/repos/acme/exact/contents/.platform.yaml":    strings.Repeat("#", maxFileSize),
+	})
+
+	tests := []struct {
+		repo    string
+		wantLen int
+		wantErr error
+	}{
+		{repo: "payments", wantLen: len("version: 1\n")},
+		{repo: "exact", wantLen: maxFileSize},
+		{repo: "big", wantErr: providers.ErrFileTooLarge},
+		{repo: "missing", wantErr: providers.ErrFileNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			data, err := p.FetchFile(context.Background(), "https://github.com/acme/"+tt.repo, "main", ".platform.yaml")
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("FetchFile() err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil || len(data) != tt.wantLen {
+				t.Fatalf("FetchFile() = %d bytes, %v, want %d bytes", len(data), err, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestBranchContains(t *testing.T) {
+
+	p := newTestProvider(t, map[string]string{
+		"/repos/acme/payments/compare/aaa...main": `{"status": "ahead"}`,
+		"/repos/acme/payments/compare/bbb...main": `{"status": "identical"}`,
+		"/repos/acme/payments/compare/ccc...main": `{"status": "diverged"}`,
+		"/repos/acme/payments/compare/ddd...main": `{"status": "behind"}`,
+	})
+
+	tests := []struct {
+		revision string
+		want     bool
+	}{
+		{"aaa", true},
+		{"bbb", true},
+		{"ccc", false},
+		{"ddd", false},
+		{"unknown", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.revision, func(t *testing.T) {
+			got, err := p.BranchContains(context.Background(), "https://github.com/acme/payments", "main", tt.revision)
+			if err != nil || got != tt.want {
+				t.Fatalf("BranchContains() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullRequest(t *testing.T) {
+
+	p := newTestProvider(t, map[string]string{
+		"/repos/acme/payments/pulls/7": `{"state": "open", "base": {"ref": "main"}, "head": {"ref": "feature", "sha": "ccc"}}`,
+	})
+
+	pr, err := p.PullRequest(context.Background(), "https://github.com/acme/payments", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := providers.PullRequest{Number: 7, Open: true, Base: "main", Head: "feature", HeadSHA: "ccc"}
+	if *pr != want {
+		t.Fatalf("PullRequest() = %+v, want %+v", *pr, want)
+	}
+
+	if _, err := p.PullRequest(context.Background(), "https://github.com/acme/payments", 8); !errors.Is(err, providers.ErrPullRequestNotFound) {
+		t.Fatalf("PullRequest() err = %v, want ErrPullRequestNotFound", err)
+	}
+}
+
+// ---- Helpers ----
+
+// newTestProvider serves bodies by path; other paths are 404.
+func newTestProvider(t *testing.T, bodies map[string]string) *Provider {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := New("")
+	p.apiURL = srv.URL
+
+	return p
+}
@@ -1,10 +1,43 @@
 package providers
 
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrFileNotFound is returned by FetchFile when the requested path
+	// does not exist at the given revision.
+	ErrFileNotFound = errors.New("file not found in repository")
+
+	// ErrFileTooLarge is returned by FetchFile rather than a truncated
+	// file.
+	ErrFileTooLarge = errors.New("file too large")
+
+	// ErrPullRequestNotFound is returned by PullRequest when the
+	// repository has no such pull request.
+	ErrPullRequestNotFound = errors.New("pull request not found")
+
+	// ErrUnsupportedRepository is returned when a repository URL does
+	// not belong to the provider's host.
+	ErrUnsupportedRepository = errors.New("unsupported repository")
+)
+
+// PullRequest is the part of a pull request that trigger rules use.
+type PullRequest struct {
+	Number  int
+	Open    bool
+	Base    string
+	Head    string
+	HeadSHA string
+}
+
 // RepositoryProvider defines the interface the control plane uses
 // to interact with source code hosts (GitHub, GitLab, Bitbucket, etc).
 //
 // Implementations are expected to handle authentication, repository
-// metadata inspection, and webhook registration.
+// metadata inspection, and webhook registration. Repository URLs of
+// other hosts are rejected with ErrUnsupportedRepository.
 type RepositoryProvider interface {
 	// ValidateRepo verifies the repository exists and is accessible.
 	ValidateRepo(repoURL string) error
@@ -12,4 +45,33 @@ type RepositoryProvider interface {
 	// DetectProjectType inspects the repository and returns its build type
 	// (e.g. node, python, go).
 	DetectProjectType(repoURL string) (string, error)
+
+	// FetchFile returns the raw contents of path at revision.
+	//
+	// An empty revision means the default branch.
+	// Returns ErrFileNotFound when the path does not exist.
+	FetchFile(
+		ctx context.Context,
+		repoURL string,
+		revision string,
+		path string,
+	) ([]byte, error)
+
+	// BranchContains reports whether revision is reachable from
+	// branch. Unknown branches and revisions are not.
+	BranchContains(
+		ctx context.Context,
+		repoURL string,
+		branch string,
+		revision string,
+	) (bool, error)
+
+	// PullRequest returns pull request number.
+	//
+	// Returns ErrPullRequestNotFound when there is none.
+	PullRequest(
+		ctx context.Context,
+		repoURL string,
+		number int,
+	) (*PullRequest, error)
 }
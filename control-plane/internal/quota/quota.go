@@ -0,0 +1,273 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+)
+
+// Limits are a team's quotas. Zero means unlimited.
+type Limits struct {
+	MaxEnvironments             int
+	MaxTTL                      time.Duration
+	MaxConcurrentRuns           int
+	MaxEnvironmentHoursPerMonth float64
+}
+
+// WithDefaults fills the quotas l leaves unset from defaults.
+func (l Limits) WithDefaults(defaults Limits) Limits {
+
+	if l.MaxEnvironments == 0 {
+		l.MaxEnvironments = defaults.MaxEnvironments
+	}
+	if l.MaxTTL == 0 {
+		l.MaxTTL = defaults.MaxTTL
+	}
+	if l.MaxConcurrentRuns == 0 {
+		l.MaxConcurrentRuns = defaults.MaxConcurrentRuns
+	}
+	if l.MaxEnvironmentHoursPerMonth == 0 {
+		l.MaxEnvironmentHoursPerMonth = defaults.MaxEnvironmentHoursPerMonth
+	}
+
+	return l
+}
+
+// Quota names, as reported to clients.
+const (
+	QuotaMaxEnvironments       = "max_environments"
+	QuotaMaxTTL                = "max_ttl"
+	QuotaMaxConcurrentRuns     = "max_concurrent_runs"
+	QuotaEnvironmentHoursMonth = "environment_hours_per_month"
+)
+
+// ErrExceeded is wrapped by every *Violation.
+var ErrExceeded = errors.New("quota exceeded")
+
+// Violation explains which quota a request hit.
+//
+// Capacity quotas (concurrency, hours) free up over time and map to
+// 429; policy quotas (max TTL) never will for the same request and
+// map to 403.
+type Violation struct {
+	Team   string
+	Quota  string
+	Limit  string
+	Used   string
+	Policy bool
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf(
+		"team %s quota %s exceeded: limit %s, %s",
+		v.Team,
+		v.Quota,
+		v.Limit,
+		v.Used,
+	)
+}
+
+func (v *Violation) Unwrap() error {
+	return ErrExceeded
+}
+
+// HTTPStatus is 403 for policy quotas and 429 for capacity quotas.
+func (v *Violation) HTTPStatus() int {
+	if v.Policy {
+		return http.StatusForbidden
+	}
+	return http.StatusTooManyRequests
+}
+
+// Usage summarises a team's consumption.
+type Usage struct {
+	ActiveEnvironments    int
+	EnvironmentHoursMonth float64
+}
+
+// EnvironmentUsage computes usage from the team's environments.
+//
+// An environment is active from submission until it is destroyed or
+// its TTL expires. Hours are counted in the current calendar month (UTC).
+func EnvironmentUsage(envs []*orchestrator.Environment, now time.Time) Usage {
+
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var u Usage
+
+	for _, env := range envs {
+		start := env.CreateWorkflow.SubmittedAt
+		end := start.Add(env.Spec.TTL)
+
+		if env.DestroyWorkflow != nil && env.DestroyWorkflow.SubmittedAt.Before(end) {
+			end = env.DestroyWorkflow.SubmittedAt
+		}
+
+		if now.Before(end) {
+			u.ActiveEnvironments++
+			end = now
+		}
+
+		if start.Before(monthStart) {
+			start = monthStart
+		}
+
+		if end.After(start) {
+			u.EnvironmentHoursMonth += end.Sub(start).Hours()
+		}
+	}
+
+	return u
+}
+
+// CheckEnvironment admits a new environment with the given TTL.
+//
+// The requested TTL is charged against the monthly budget up front,
+// since the environment may live that long.
+func CheckEnvironment(
+	team string,
+	limits Limits,
+	envs []*orchestrator.Environment,
+	ttl time.Duration,
+	now time.Time,
+) error {
+
+	if limits.MaxTTL > 0 && ttl > limits.MaxTTL {
+		return &Violation{
+			Team:   team,
+			Quota:  QuotaMaxTTL,
+			Limit:  limits.MaxTTL.String(),
+			Used:   "requested " + ttl.String(),
+			Policy: true,
+		}
+	}
+
+	usage := EnvironmentUsage(envs, now)
+
+	if limits.MaxEnvironments > 0 && usage.ActiveEnvironments >= limits.MaxEnvironments {
+		return &Violation{
+			Team:  team,
+			Quota: QuotaMaxEnvironments,
+			Limit: fmt.Sprint(limits.MaxEnvironments),
+			Used:  fmt.Sprintf("%d active", usage.ActiveEnvironments),
+		}
+	}
+
+	if limits.MaxEnvironmentHoursPerMonth > 0 &&
+		usage.EnvironmentHoursMonth+ttl.Hours() > limits.MaxEnvironmentHoursPerMonth {
+		return &Violation{
+			Team:  team,
+			Quota: QuotaEnvironmentHoursMonth,
+			Limit: fmt.Sprintf("%.1fh", limits.MaxEnvironmentHoursPerMonth),
+			Used: fmt.Sprintf(
+				"%.1fh used this month, %.1fh requested",
+				usage.EnvironmentHoursMonth,
+				ttl.Hours(),
+			),
+		}
+	}
+
+	return nil
+}
+
+// CheckExtension admits extending env's TTL to ttl.
+//
+// Only the added time is charged against the monthly budget; the
+// environment already counts towards the active limit.
+func CheckExtension(
+	team string,
+	limits Limits,
+	envs []*orchestrator.Environment,
+	env *orchestrator.Environment,
+	ttl time.Duration,
+	now time.Time,
+) error {
+
+	if limits.MaxTTL > 0 && ttl > limits.MaxTTL {
+		return &Violation{
+			Team:   team,
+			Quota:  QuotaMaxTTL,
+			Limit:  limits.MaxTTL.String(),
+			Used:   "requested " + ttl.String(),
+			Policy: true,
+		}
+	}
+
+	added := ttl - env.Spec.TTL
+	usage := EnvironmentUsage(envs, now)
+
+	if limits.MaxEnvironmentHoursPerMonth > 0 &&
+		usage.EnvironmentHoursMonth+added.Hours() > limits.MaxEnvironmentHoursPerMonth {
+		return &Violation{
+			Team:  team,
+			Quota: QuotaEnvironmentHoursMonth,
+			Limit: fmt.Sprintf("%.1fh", limits.MaxEnvironmentHoursPerMonth),
+			Used: fmt.Sprintf(
+				"%.1fh used this month, %.1fh more requested",
+				usage.EnvironmentHoursMonth,
+				added.Hours(),
+			),
+		}
+	}
+
+	return nil
+}
+
+// CheckRun admits a new CI run for a team owning services.
+//
+// Running and pending CI workflows are counted live from the
+// execution plane, so runs submitted by other replicas count too.
+func CheckRun(
+	ctx context.Context,
+	search orchestrator.WorkflowSearch,
+	team string,
+	limits Limits,
+	services []string,
+) error {
+
+	if limits.MaxConcurrentRuns <= 0 {
+		return nil
+	}
+
+	active := 0
+
+	for _, service := range services {
+		q := orchestrator.WorkflowQuery{
+			Service: service,
+			Team:    team,
+			Type:    orchestrator.WorkflowTypeCI,
+			Active:  true,
+			Limit:   orchestrator.MaxSearchLimit,
+		}
+
+		for {
+			page, err := search.Search(ctx, q)
+			if err != nil {
+				return fmt.Errorf("count active runs: %w", err)
+			}
+
+			active += len(page.Items)
+
+			if active >= limits.MaxConcurrentRuns {
+				return &Violation{
+					Team:  team,
+					Quota: QuotaMaxConcurrentRuns,
+					Limit: fmt.Sprint(limits.MaxConcurrentRuns),
+					Used:  fmt.Sprintf("%d or more active", active),
+				}
+			}
+
+			if page.Continue == "" {
+				break
+			}
+			q.Continue = page.Continue
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,296 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+)
+
+var now = time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+func TestEnvironmentUsage(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		envs       []*orchestrator.Environment
+		wantActive int
+		wantHours  float64
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:       "active",
+			envs:       []*orchestrator.Environment{env(now.Add(-2*time.Hour), 8*time.Hour, nil)},
+			wantActive: 1,
+			wantHours:  2,
+		},
+		{
+			name:      "expired",
+			envs:      []*orchestrator.Environment{env(now.Add(-10*time.Hour), 4*time.Hour, nil)},
+			wantHours: 4,
+		},
+		{
+			name:      "destroyed before expiry",
+			envs:      []*orchestrator.Environment{env(now.Add(-10*time.Hour), 8*time.Hour, ptr(now.Add(-9*time.Hour)))},
+			wantHours: 1,
+		},
+		{
+			name:       "destroy submitted after expiry",
+			envs:       []*orchestrator.Environment{env(now.Add(-2*time.Hour), time.Hour, ptr(now.Add(-30*time.Minute)))},
+			wantActive: 0,
+			wantHours:  1,
+		},
+		{
+			name:       "started last month",
+			envs:       []*orchestrator.Environment{env(time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC), 30*24*time.Hour, nil)},
+			wantActive: 1,
+			wantHours:  9*24 + 12,
+		},
+		{
+			name:      "ended last month",
+			envs:      []*orchestrator.Environment{env(time.Date(2026, time.February, 20, 0, 0, 0, 0, time.UTC), 24*time.Hour, nil)},
+			wantHours: 0,
+		},
+		{
+			name: "several",
+			envs: []*orchestrator.Environment{
+				env(now.Add(-time.Hour), 2*time.Hour, nil),
+				env(now.Add(-3*time.Hour), 2*time.Hour, nil),
+				env(now.Add(-30*time.Minute), time.Hour, nil),
+			},
+			wantActive: 2,
+			wantHours:  3.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := EnvironmentUsage(tt.envs, now)
+
+			if u.ActiveEnvironments != tt.wantActive {
+				t.Fatalf("ActiveEnvironments = %d, want %d", u.ActiveEnvironments, tt.wantActive)
+			}
+			if math.Abs(u.EnvironmentHoursMonth-tt.wantHours) > 1e-9 {
+				t.Fatalf("EnvironmentHoursMonth = %v, want %v", u.EnvironmentHoursMonth, tt.wantHours)
+			}
+		})
+	}
+}
+
+func TestCheckEnvironment(t *testing.T) {
+
+	active := []*orchestrator.Environment{
+		env(now.Add(-time.Hour), 4*time.Hour, nil),
+		env(now.Add(-time.Hour), 4*time.Hour, nil),
+	}
+
+	tests := []struct {
+		name       string
+		limits     Limits
+		envs       []*orchestrator.Environment
+		ttl        time.Duration
+		wantQuota  string
+		wantStatus int
+	}{
+		{
+			name: "unlimited",
+			envs: active,
+			ttl:  100 * time.Hour,
+		},
+		{
+			name:   "within limits",
+			limits: Limits{MaxEnvironments: 3, MaxTTL: 8 * time.Hour, MaxEnvironmentHoursPerMonth: 10},
+			envs:   active,
+			ttl:    8 * time.Hour,
+		},
+		{
+			name:       "ttl too long",
+			limits:     Limits{MaxTTL: 8 * time.Hour},
+			ttl:        9 * time.Hour,
+			wantQuota:  QuotaMaxTTL,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "too many environments",
+			limits:     Limits{MaxEnvironments: 2},
+			envs:       active,
+			ttl:        time.Hour,
+			wantQuota:  QuotaMaxEnvironments,
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "requested ttl exceeds monthly hours",
+			limits:     Limits{MaxEnvironmentHoursPerMonth: 10},
+			envs:       active,
+			ttl:        9 * time.Hour,
+			wantQuota:  QuotaEnvironmentHoursMonth,
+			wantStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckEnvironment("payments", tt.limits, tt.envs, tt.ttl, now)
+
+			if tt.wantQuota == "" {
+				if err != nil {
+					t.Fatalf("CheckEnvironment() = %v, want nil", err)
+				}
+				return
+			}
+
+			var v *Violation
+			if !errors.As(err, &v) || !errors.Is(err, ErrExceeded) {
+				t.Fatalf("CheckEnvironment() = %v, want a violation", err)
+			}
+			if v.Quota != tt.wantQuota || v.HTTPStatus() != tt.wantStatus {
+				t.Fatalf("violation = %s (%d), want %s (%d)", v.Quota, v.HTTPStatus(), tt.wantQuota, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCheckExtension(t *testing.T) {
+
+	extended := env(now.Add(-time.Hour), 4*time.Hour, nil)
+	envs := []*orchestrator.Environment{extended, env(now.Add(-time.Hour), 4*time.Hour, nil)}
+
+	tests := []struct {
+		name      string
+		limits    Limits
+		ttl       time.Duration
+		wantQuota string
+	}{
+		{"unlimited", Limits{}, 48 * time.Hour, ""},
+		{"within max ttl", Limits{MaxTTL: 8 * time.Hour}, 8 * time.Hour, ""},
+		{"over max ttl", Limits{MaxTTL: 8 * time.Hour}, 9 * time.Hour, QuotaMaxTTL},
+		{"active limit not charged", Limits{MaxEnvironments: 2}, 8 * time.Hour, ""},
+		{"added hours within budget", Limits{MaxEnvironmentHoursPerMonth: 10}, 12 * time.Hour, ""},
+		{"added hours over budget", Limits{MaxEnvironmentHoursPerMonth: 10}, 13 * time.Hour, QuotaEnvironmentHoursMonth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckExtension("team-a", tt.limits, envs, extended, tt.ttl, now)
+
+			if tt.wantQuota == "" {
+				if err != nil {
+					t.Fatalf("CheckExtension() = %v", err)
+				}
+				return
+			}
+
+			var v *Violation
+			if !errors.As(err, &v) || v.Quota != tt.wantQuota {
+				t.Fatalf("CheckExtension() = %v, want %s", err, tt.wantQuota)
+			}
+		})
+	}
+}
+
+func TestCheckRun(t *testing.T) {
+
+	search := &fakeSearch{
+		active: map[string]int{"payments": 2, "billing": 1},
+		limit:  1,
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		services []string
+		wantErr  bool
+	}{
+		{"unlimited", 0, []string{"payments", "billing"}, false},
+		{"under the limit", 4, []string{"payments", "billing"}, false},
+		{"at the limit", 3, []string{"payments", "billing"}, true},
+		{"counts across pages", 2, []string{"payments"}, true},
+		{"other services not counted", 2, []string{"billing"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRun(context.Background(), search, "team-a", Limits{MaxConcurrentRuns: tt.limit}, tt.services)
+
+			var v *Violation
+			if tt.wantErr != errors.As(err, &v) {
+				t.Fatalf("CheckRun() = %v, want violation %v", err, tt.wantErr)
+			}
+			if tt.wantErr && v.Quota != QuotaMaxConcurrentRuns {
+				t.Fatalf("violation = %s", v.Quota)
+			}
+		})
+	}
+
+	for _, q := range search.queries {
+		if !q.Active || q.Type != orchestrator.WorkflowTypeCI || q.Team != "team-a" {
+			t.Fatalf("query = %+v, want active CI runs of team-a", q)
+		}
+	}
+}
+
+func TestLimitsWithDefaults(t *testing.T) {
+
+	defaults := Limits{MaxEnvironments: 5, MaxTTL: time.Hour, MaxConcurrentRuns: 3, MaxEnvironmentHoursPerMonth: 100}
+
+	got := Limits{MaxEnvironments: 1}.WithDefaults(defaults)
+	want := Limits{MaxEnvironments: 1, MaxTTL: time.Hour, MaxConcurrentRuns: 3, MaxEnvironmentHoursPerMonth: 100}
+
+	if got != want {
+		t.Fatalf("WithDefaults() = %+v, want %+v", got, want)
+	}
+}
+
+// ---- Helpers ----
+
+func env(submitted time.Time, ttl time.Duration, destroyed *time.Time) *orchestrator.Environment {
+
+	e := &orchestrator.Environment{
+		Spec:           orchestrator.EnvironmentSpec{Name: "env", TTL: ttl},
+		CreateWorkflow: orchestrator.WorkflowReference{SubmittedAt: submitted},
+	}
+
+	if destroyed != nil {
+		e.DestroyWorkflow = &orchestrator.WorkflowReference{SubmittedAt: *destroyed}
+	}
+
+	return e
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+// fakeSearch serves active runs per service, limit items a page.
+type fakeSearch struct {
+	active  map[string]int
+	limit   int
+	queries []orchestrator.WorkflowQuery
+}
+
+func (s *fakeSearch) Search(_ context.Context, q orchestrator.WorkflowQuery) (*orchestrator.WorkflowPage, error) {
+
+	s.queries = append(s.queries, q)
+
+	offset := 0
+	if q.Continue != "" {
+		offset = int(q.Continue[0] - '0')
+	}
+
+	page := &orchestrator.WorkflowPage{}
+	for i := offset; i < s.active[q.Service] && len(page.Items) < s.limit; i++ {
+		page.Items = append(page.Items, orchestrator.WorkflowSummary{Service: q.Service, Phase: "Running"})
+	}
+
+	if next := offset + len(page.Items); next < s.active[q.Service] {
+		page.Continue = string(rune('0' + next))
+	}
+
+	return page, nil
+}
@@ -0,0 +1,176 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/logging"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/problem"
+)
+
+// Class selects the budget a request is charged against.
+type Class string
+
+const (
+	// ClassRead covers cheap requests: reads, registrations, renders.
+	ClassRead Class = "read"
+
+	// ClassSubmit covers requests that submit workflows to the
+	// execution plane.
+	ClassSubmit Class = "submit"
+
+	// ClassClient covers every request from one client IP, charged
+	// before authentication.
+	ClassClient Class = "client"
+)
+
+// Middleware charges each request against its class's budget.
+//
+// Requests are keyed by authenticated principal, or by client IP for
+// anonymous requests, so it must run inside the authentication
+// middleware. Classes without a limiter are not limited, and exempt
+// paths (probes) are never limited.
+//
+// Every limited response carries RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset; rejections add Retry-After and a 429 problem.
+func Middleware(
+	limiters map[Class]*Limiter,
+	classify func(*http.Request) Class,
+	exempt []string,
+	logger *zap.Logger,
+) func(http.Handler) http.Handler {
+
+	skip := make(map[string]bool, len(exempt))
+	for _, p := range exempt {
+		skip[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			class := classify(r)
+
+			limiter := limiters[class]
+			if limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !admit(w, r, limiter, class, clientKey(r), logger) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientMiddleware charges every request against its client IP's
+// budget before authentication, so that unauthenticated requests,
+// which the per-principal budgets cannot tell apart, are bounded too.
+// Exempt paths (probes) are never limited; a nil limiter disables it.
+func ClientMiddleware(
+	limiter *Limiter,
+	exempt []string,
+	logger *zap.Logger,
+) func(http.Handler) http.Handler {
+
+	skip := make(map[string]bool, len(exempt))
+	for _, p := range exempt {
+		skip[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if limiter == nil || skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !admit(w, r, limiter, ClassClient, clientIP(r), logger) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// admit takes a token for key and sets the RateLimit-* headers. On
+// rejection it writes the 429 problem and returns false.
+func admit(
+	w http.ResponseWriter,
+	r *http.Request,
+	limiter *Limiter,
+	class Class,
+	key string,
+	logger *zap.Logger,
+) bool {
+
+	res := limiter.Allow(string(class) + "|" + key)
+
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+
+	if res.Allowed {
+		return true
+	}
+
+	retry := seconds(res.RetryAfter)
+
+	logging.FromContext(r.Context(), logger).Warn("rate limit exceeded",
+		zap.String("client", key),
+		zap.String("class", string(class)),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	h.Set("Retry-After", strconv.Itoa(retry))
+	problem.Write(w, http.StatusTooManyRequests, fmt.Sprintf(
+		"%s rate limit of %d requests exceeded; retry in %ds",
+		class,
+		res.Limit,
+		retry,
+	))
+	return false
+}
+
+// clientKey identifies who is being limited.
+func clientKey(r *http.Request) string {
+
+	if p := auth.PrincipalFrom(r.Context()); p != nil && p.Method != auth.MethodAnonymous {
+		return p.ID()
+	}
+
+	return clientIP(r)
+}
+
+func clientIP(r *http.Request) string {
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// seconds rounds up, so clients never retry too early.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
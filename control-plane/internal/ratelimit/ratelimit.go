@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Budget is a token bucket: Rate requests per second sustained, with
+// bursts of up to Burst. A zero Rate disables the budget.
+type Budget struct {
+	Rate  float64
+	Burst int
+}
+
+// Result describes a single admission decision, in the terms of the
+// RateLimit-* response headers.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Limiter keeps one token bucket per key (principal or client IP).
+//
+// Buckets idle for longer than it takes them to refill are dropped,
+// since a new bucket starts full anyway.
+type Limiter struct {
+	budget Budget
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// sweepInterval bounds how often idle buckets are collected.
+const sweepInterval = time.Minute
+
+func NewLimiter(budget Budget) *Limiter {
+
+	if budget.Burst < 1 {
+		budget.Burst = 1
+	}
+
+	return &Limiter{
+		budget:  budget,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes one token from key's bucket.
+func (l *Limiter) Allow(key string) Result {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{
+			limiter: rate.NewLimiter(rate.Limit(l.budget.Rate), l.budget.Burst),
+		}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	allowed := b.limiter.AllowN(now, 1)
+	tokens := b.limiter.TokensAt(now)
+
+	res := Result{
+		Allowed:   allowed,
+		Limit:     l.budget.Burst,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     l.refill(float64(l.budget.Burst) - tokens),
+	}
+
+	if !allowed {
+		res.RetryAfter = l.refill(1 - tokens)
+	}
+
+	return res
+}
+
+// refill is how long it takes to regain n tokens.
+func (l *Limiter) refill(n float64) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return time.Duration(n / l.budget.Rate * float64(time.Second))
+}
+
+func (l *Limiter) sweep(now time.Time) {
+
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	idle := l.refill(float64(l.budget.Burst))
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idle {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+//
+// ---- Concurrency ----
+//
+
+// Concurrency caps how many operations per key are in progress at
+// once, e.g. workflow submission requests per service, independently
+// of request rates. It counts requests being served, not the workflows
+// they submit: once a submission returns, its slot is free, however
+// long the workflow runs.
+type Concurrency struct {
+	max int
+
+	mu     sync.Mutex
+	active map[string]int
+}
+
+// NewConcurrency allows max concurrent operations per key; zero or
+// less means unlimited.
+func NewConcurrency(max int) *Concurrency {
+	return &Concurrency{
+		max:    max,
+		active: make(map[string]int),
+	}
+}
+
+// Acquire reserves a slot for key. The returned release func must be
+// called exactly once when ok is true.
+func (f *Concurrency) Acquire(key string) (release func(), ok bool) {
+
+	if f == nil || f.max <= 0 {
+		return func() {}, true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.active[key] >= f.max {
+		return nil, false
+	}
+	f.active[key]++
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+
+			f.active[key]--
+			if f.active[key] <= 0 {
+				delete(f.active, key)
+			}
+		})
+	}, true
+}
+
+// Max is the per-key cap, zero when unlimited.
+func (f *Concurrency) Max() int {
+	if f == nil || f.max <= 0 {
+		return 0
+	}
+	return f.max
+}
@@ -0,0 +1,201 @@
+package ratelimit
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+
+	type step struct {
+		after         time.Duration
+		key           string
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration
+	}
+
+	tests := []struct {
+		name   string
+		budget Budget
+		steps  []step
+	}{
+		{
+			name:   "burst then refill",
+			budget: Budget{Rate: 1, Burst: 2},
+			steps: []step{
+				{key: "a", wantAllowed: true, wantRemaining: 1},
+				{key: "a", wantAllowed: true, wantRemaining: 0},
+				{key: "a", wantAllowed: false, wantRemaining: 0, wantRetry: time.Second},
+				{after: 500 * time.Millisecond, key: "a", wantAllowed: false, wantRemaining: 0, wantRetry: 500 * time.Millisecond},
+				{after: 500 * time.Millisecond, key: "a", wantAllowed: true, wantRemaining: 0},
+			},
+		},
+		{
+			name:   "keys have separate buckets",
+			budget: Budget{Rate: 1, Burst: 1},
+			steps: []step{
+				{key: "a", wantAllowed: true},
+				{key: "a", wantAllowed: false, wantRetry: time.Second},
+				{key: "b", wantAllowed: true},
+			},
+		},
+		{
+			name:   "burst of at least one",
+			budget: Budget{Rate: 10},
+			steps: []step{
+				{key: "a", wantAllowed: true},
+				{key: "a", wantAllowed: false, wantRetry: 100 * time.Millisecond},
+			},
+		},
+		{
+			name:   "idle bucket refills to its burst",
+			budget: Budget{Rate: 1, Burst: 2},
+			steps: []step{
+				{key: "a", wantAllowed: true, wantRemaining: 1},
+				{key: "a", wantAllowed: true, wantRemaining: 0},
+				{after: 2 * sweepInterval, key: "a", wantAllowed: true, wantRemaining: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := time.Unix(1_700_000_000, 0)
+
+			l := NewLimiter(tt.budget)
+			l.now = func() time.Time { return clock }
+
+			for i, s := range tt.steps {
+				clock = clock.Add(s.after)
+
+				res := l.Allow(s.key)
+
+				if res.Allowed != s.wantAllowed || res.Remaining != s.wantRemaining || res.RetryAfter != s.wantRetry {
+					t.Fatalf(
+						"step %d: Allow(%q) = allowed %v, remaining %d, retry %v; want %v, %d, %v",
+						i, s.key, res.Allowed, res.Remaining, res.RetryAfter,
+						s.wantAllowed, s.wantRemaining, s.wantRetry,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+
+	clock := time.Unix(1_700_000_000, 0)
+
+	l := NewLimiter(Budget{Rate: 1, Burst: 5})
+	l.now = func() time.Time { return clock }
+
+	l.Allow("a")
+	clock = clock.Add(2 * sweepInterval)
+	l.Allow("b")
+
+	if _, ok := l.buckets["a"]; ok {
+		t.Fatal("idle bucket was not swept")
+	}
+	if _, ok := l.buckets["b"]; !ok {
+		t.Fatal("active bucket was swept")
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		max      int
+		acquire  int
+		wantHeld int
+	}{
+		{"unlimited", 0, 10, 10},
+		{"under the cap", 3, 2, 2},
+		{"at the cap", 3, 5, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConcurrency(tt.max)
+
+			var releases []func()
+			for i := 0; i < tt.acquire; i++ {
+				if release, ok := c.Acquire("svc"); ok {
+					releases = append(releases, release)
+				}
+			}
+
+			if len(releases) != tt.wantHeld {
+				t.Fatalf("held %d slots, want %d", len(releases), tt.wantHeld)
+			}
+
+			if _, ok := c.Acquire("other"); !ok {
+				t.Fatal("keys should not share slots")
+			}
+
+			// Releasing twice must free the slot only once.
+			releases[0]()
+			releases[0]()
+
+			if tt.max > 0 {
+				if _, ok := c.Acquire("svc"); !ok {
+					t.Fatal("released slot was not freed")
+				}
+				if tt.wantHeld == tt.max {
+					if _, ok := c.Acquire("svc"); ok {
+						t.Fatal("double release freed two slots")
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrencyParallel(t *testing.T) {
+
+	c := NewConcurrency(4)
+
+	var (
+		mu      sync.Mutex
+		held    int
+		maxHeld int
+		wg      sync.WaitGroup
+	)
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			release, ok := c.Acquire("svc")
+			if !ok {
+				return
+			}
+			defer release()
+
+			mu.Lock()
+			held++
+			if held > maxHeld {
+				maxHeld = held
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			held--
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	if maxHeld > 4 {
+		t.Fatalf("%d concurrent holders, want at most 4", maxHeld)
+	}
+	if _, ok := c.Acquire("svc"); !ok {
+		t.Fatal("slots were not all released")
+	}
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/audit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+)
+
+// auditMemoryRecords is how many records are kept without an audit file.
+const auditMemoryRecords = 10000
+
+func newAuditStore(cfg config.AuditConfig, logger *zap.Logger) (audit.Store, error) {
+
+	if cfg.File == "" {
+		logger.Warn("audit.file (AUDIT_LOG_FILE) not set: audit records are kept in memory only")
+		return audit.NewMemoryStore(auditMemoryRecords), nil
+	}
+
+	return audit.OpenFileStore(cfg.File)
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+)
+
+// publicPaths are served without authentication so that kubelet
+// probes keep working.
+var publicPaths = []string{
+	"/healthz",
+	"/readyz",
+}
+
+// newAuthenticators builds the configured authenticators.
+//
+// Running without any is refused unless authentication is explicitly
+// disabled: a misconfigured deployment must not silently become open.
+func newAuthenticators(
+	ctx context.Context,
+	cfg config.AuthConfig,
+	logger *zap.Logger,
+) ([]auth.Authenticator, error) {
+
+	if cfg.Disabled {
+		logger.Warn("API authentication disabled: every request runs as anonymous")
+		return nil, nil
+	}
+
+	var authenticators []auth.Authenticator
+
+	if cfg.TokensFile != "" {
+		tokens, err := auth.LoadTokenAuthenticator(cfg.TokensFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, tokens)
+	}
+
+	if cfg.OIDC.Issuer != "" {
+		oidc, err := auth.NewOIDCAuthenticator(
+			ctx,
+			auth.OIDCConfig(cfg.OIDC),
+			&http.Client{Timeout: 10 * time.Second},
+		)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, oidc)
+	}
+
+	if len(authenticators) == 0 {
+		return nil, errors.New(
+			"no API authentication configured: set auth.tokens_file (AUTH_TOKENS_FILE) and/or " +
+				"auth.oidc.issuer (OIDC_ISSUER), or auth.disabled (AUTH_DISABLED) for local development",
+		)
+	}
+
+	return authenticators, nil
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+// executionBackend bundles everything the composition root needs
+// from the execution plane.
+type executionBackend struct {
+	exec           executor.WorkflowExecutor
+	templates      executor.TemplateReader
+	workflows      executor.WorkflowLister
+	defaultCluster string
+	clusters       []string
+}
+
+func newExecutionBackend(
+	cfg config.ExecutorConfig,
+	logger *zap.Logger,
+) (*executionBackend, error) {
+
+	switch cfg.Backend {
+
+	case config.BackendArgo, config.BackendJobs:
+		return newKubernetesBackend(cfg)
+
+	case config.BackendFake:
+		return newSimulatedBackend(cfg, logger)
+
+	default:
+		return nil, fmt.Errorf(
+			"unknown executor backend %q (want %s, %s or %s)",
+			cfg.Backend,
+			config.BackendArgo,
+			config.BackendJobs,
+			config.BackendFake,
+		)
+	}
+}
+
+// newKubernetesBackend connects to every configured cluster and runs
+// workflows either through Argo or as plain Jobs.
+func newKubernetesBackend(cfg config.ExecutorConfig) (*executionBackend, error) {
+
+	clusterSources, err := executor.DiscoverClusterSources(
+		cfg.ClusterConfigDir,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range cfg.Clusters {
+		clusterSources = append(clusterSources, executor.ClusterSource{
+			Name:    c.Name,
+			Context: c.Context,
+		})
+	}
+
+	clients, err := executor.NewClients(
+		cfg.DefaultCluster,
+		clusterSources,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := &executionBackend{
+		defaultCluster: clients.DefaultCluster(),
+		clusters:       clients.ClusterNames(),
+	}
+
+	if cfg.Backend == config.BackendJobs {
+		jobsExecutor := executor.NewJobsExecutor(clients)
+		backend.exec, backend.templates, backend.workflows = jobsExecutor, jobsExecutor, jobsExecutor
+	} else {
+		argoExecutor := executor.NewArgoSDKExecutor(clients)
+		backend.exec, backend.templates, backend.workflows = argoExecutor, argoExecutor, argoExecutor
+	}
+
+	return backend, nil
+}
+
+// newSimulatedBackend runs entirely in memory.
+//
+// Every configured cluster name is accepted so that placement
+// behaves as it would against real clusters.
+func newSimulatedBackend(
+	cfg config.ExecutorConfig,
+	logger *zap.Logger,
+) (*executionBackend, error) {
+
+	simulation, err := executor.LoadSimulation(cfg.SimulationScript)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := executor.LoadTemplates(cfg.SimulationTemplateDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Warn("using simulated executor: no workflows will run",
+		zap.Int("templates", len(templates)),
+		zap.String("template_dirs", strings.Join(cfg.SimulationTemplateDirs, ",")),
+	)
+
+	clusters := []string{cfg.DefaultCluster}
+	for _, c := range cfg.Clusters {
+		clusters = append(clusters, c.Name)
+	}
+
+	simulated := executor.NewSimulatedExecutor(cfg.DefaultCluster, simulation, templates)
+
+	return &executionBackend{
+		exec:           simulated,
+		templates:      simulated,
+		workflows:      simulated,
+		defaultCluster: cfg.DefaultCluster,
+		clusters:       clusters,
+	}, nil
+}
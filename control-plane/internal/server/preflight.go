@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/contract"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+)
+
+// preflightTimeout bounds the startup template check across every
+// cluster and namespace.
+const preflightTimeout = 30 * time.Second
+
+// preflightTemplates checks the templates of every blueprint, and
+// every CI template, in every execution namespace of every cluster
+// against the parameters the orchestrators submit (see
+// contract.Preflight).
+//
+// Findings are logged. With templates.strict_preflight, a template
+// requiring parameters the platform never submits fails startup: every
+// submission to it would be rejected.
+func preflightTemplates(
+	cfg *config.Config,
+	backend *executionBackend,
+	templates *catalog.Catalog,
+	blueprints *blueprint.Registry,
+	logger *zap.Logger,
+) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+	defer cancel()
+
+	findings := contract.Preflight(
+		ctx,
+		backend.templates,
+		preflightTargets(cfg.Executor, backend.clusters),
+		preflightExpectations(templates, blueprints, logger),
+	)
+
+	var violations []string
+
+	for _, f := range findings {
+		fields := []zap.Field{
+			zap.String("template", f.Result.Template),
+			zap.String("cluster", f.Target.Cluster),
+			zap.String("namespace", f.Target.Namespace),
+		}
+
+		if f.Err != nil {
+			logger.Warn("template not readable in execution namespace", append(fields, zap.Error(f.Err))...)
+			continue
+		}
+
+		if len(f.Result.Missing) > 0 {
+			logger.Error("template requires parameters the platform does not submit",
+				append(fields, zap.Strings("parameters", f.Result.Missing))...,
+			)
+			violations = append(violations, fmt.Sprintf(
+				"%s in %s/%s: %s",
+				f.Result.Template,
+				f.Target.Cluster,
+				f.Target.Namespace,
+				strings.Join(f.Result.Missing, ", "),
+			))
+		}
+
+		if len(f.Result.Undeclared) > 0 {
+			logger.Warn("template does not declare parameters the platform submits",
+				append(fields, zap.Strings("parameters", f.Result.Undeclared))...,
+			)
+		}
+	}
+
+	if len(violations) > 0 && cfg.Templates.StrictPreflight {
+		return fmt.Errorf(
+			"%w (templates.strict_preflight): missing required parameters: %s",
+			contract.ErrViolation,
+			strings.Join(violations, "; "),
+		)
+	}
+
+	return nil
+}
+
+// preflightTargets is every configured execution namespace in every
+// cluster. Team namespaces registered through the API are not known
+// yet and are checked on submission only.
+func preflightTargets(
+	cfg config.ExecutorConfig,
+	clusters []string,
+) []executor.Target {
+
+	seen := map[string]bool{cfg.Namespace: true}
+	for _, ns := range cfg.ServiceNamespaces {
+		seen[ns] = true
+	}
+	for _, ns := range cfg.TeamNamespaces {
+		seen[ns] = true
+	}
+
+	namespaces := make([]string, 0, len(seen))
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	targets := make([]executor.Target, 0, len(clusters)*len(namespaces))
+	for _, cluster := range clusters {
+		for _, ns := range namespaces {
+			targets = append(targets, executor.Target{Cluster: cluster, Namespace: ns})
+		}
+	}
+
+	return targets
+}
+
+// preflightExpectations resolves the environment templates of every
+// blueprint and lists every CI and deploy template. Unresolvable
+// templates are left to the readiness check and to submission.
+func preflightExpectations(
+	templates *catalog.Catalog,
+	blueprints *blueprint.Registry,
+	logger *zap.Logger,
+) []contract.Expectation {
+
+	var expectations []contract.Expectation
+
+	seen := make(map[string]bool)
+
+	for _, bp := range blueprints.List() {
+		named := map[catalog.Purpose]string{
+			catalog.PurposeEnvCreate:  bp.CreateTemplate,
+			catalog.PurposeEnvDestroy: bp.DestroyTemplate,
+			catalog.PurposeEnvTTL:     bp.TTLTemplate,
+		}
+
+		for _, purpose := range requiredTemplates {
+			name := named[purpose]
+			if name == "" {
+				t, err := templates.Resolve(purpose, "")
+				if err != nil {
+					logger.Debug("skipping template preflight", zap.String("purpose", string(purpose)), zap.Error(err))
+					continue
+				}
+				name = t.Name
+			}
+
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			expectations = append(expectations, contract.Expectation{
+				Template:   name,
+				Parameters: orchestrator.BlueprintParameters(bp, purpose),
+			})
+		}
+	}
+
+	for _, t := range templates.List() {
+		if t.Purpose != catalog.PurposeCI && t.Purpose != catalog.PurposeDeploy {
+			continue
+		}
+
+		expectations = append(expectations, contract.Expectation{
+			Template:       t.Name,
+			Parameters:     orchestrator.SubmittedParameters(t.Purpose),
+			CallerSupplied: true,
+		})
+	}
+
+	return expectations
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/ratelimit"
+)
+
+// submitRoutes submit workflows to the execution plane and are charged
+// against the submit budget.
+var submitRoutes = []string{
+	"POST /api/v1/environments",
+	"DELETE /api/v1/environments/{name}",
+	"POST /api/v1/environments/{name}/extend",
+	"POST /api/v1/environments/{name}/deployments",
+	"POST /api/v1/services/{name}/runs",
+	"POST /api/v1/pipelines",
+}
+
+// newClientLimiter is the per-IP budget charged before authentication,
+// nil when disabled.
+func newClientLimiter(cfg config.RateLimitConfig) *ratelimit.Limiter {
+
+	if cfg.ClientRate <= 0 {
+		return nil
+	}
+
+	return ratelimit.NewLimiter(ratelimit.Budget{
+		Rate:  cfg.ClientRate,
+		Burst: cfg.ClientBurst,
+	})
+}
+
+func newRateLimiters(cfg config.RateLimitConfig) map[ratelimit.Class]*ratelimit.Limiter {
+
+	limiters := make(map[ratelimit.Class]*ratelimit.Limiter)
+
+	if cfg.ReadRate > 0 {
+		limiters[ratelimit.ClassRead] = ratelimit.NewLimiter(ratelimit.Budget{
+			Rate:  cfg.ReadRate,
+			Burst: cfg.ReadBurst,
+		})
+	}
+
+	if cfg.SubmitRate > 0 {
+		limiters[ratelimit.ClassSubmit] = ratelimit.NewLimiter(ratelimit.Budget{
+			Rate:  cfg.SubmitRate,
+			Burst: cfg.SubmitBurst,
+		})
+	}
+
+	return limiters
+}
+
+// classifyRequest matches submitRoutes with the same pattern syntax
+// as the API router.
+func classifyRequest() func(*http.Request) ratelimit.Class {
+
+	mux := http.NewServeMux()
+	for _, route := range submitRoutes {
+		mux.Handle(route, http.NotFoundHandler())
+	}
+
+	return func(r *http.Request) ratelimit.Class {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			return ratelimit.ClassSubmit
+		}
+		return ratelimit.ClassRead
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/audit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/health"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/providers/github"
+)
+
+// readinessTimeout bounds each readiness check; kubelet probes
+// time out after one second by default, so checks run concurrently.
+const readinessTimeout = 900 * time.Millisecond
+
+// requiredTemplates must resolve for environments to be created,
+// destroyed and expired. CI templates are per language and optional.
+var requiredTemplates = []catalog.Purpose{
+	catalog.PurposeEnvCreate,
+	catalog.PurposeEnvDestroy,
+	catalog.PurposeEnvTTL,
+}
+
+// pinger is implemented by stores with a backend worth checking.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// newReadiness registers the dependency checks behind /readyz.
+//
+// The default cluster, the environment templates and the audit log
+// are critical: without them nothing can be submitted or recorded.
+// Further clusters and the repository provider only degrade the
+// control plane, as every replica would be affected alike.
+//
+// The service registry is in memory and needs no check.
+func newReadiness(
+	backend *executionBackend,
+	templates *catalog.Catalog,
+	templateNamespace string,
+	auditLog audit.Store,
+	repositories *github.Provider,
+	logger *zap.Logger,
+) *health.Checker {
+
+	checker := health.NewChecker(readinessTimeout, logger)
+
+	for _, cluster := range backend.clusters {
+		target := executor.Target{Cluster: cluster, Namespace: templateNamespace}
+
+		checker.Register(health.NewCheck("execution-plane/"+cluster, func(ctx context.Context) error {
+			_, err := backend.templates.ListTemplates(ctx, target, catalog.LabelCatalog+"=true")
+			return err
+		}), cluster == backend.defaultCluster)
+	}
+
+	checker.Register(health.NewCheck("templates", func(context.Context) error {
+		return checkTemplates(templates)
+	}), true)
+
+	if p, ok := auditLog.(pinger); ok {
+		checker.Register(health.NewCheck("audit-store", p.Ping), true)
+	}
+
+	checker.Register(health.NewCheck("github", repositories.Ping), false)
+
+	return checker
+}
+
+// checkTemplates reads the catalog snapshot rather than the execution
+// plane, which the execution-plane checks already cover.
+func checkTemplates(templates *catalog.Catalog) error {
+
+	if templates.RefreshedAt().IsZero() {
+		return errors.New("template catalog has not been refreshed yet")
+	}
+
+	var missing []string
+	for _, purpose := range requiredTemplates {
+		if _, err := templates.Resolve(purpose, ""); err != nil {
+			missing = append(missing, err.Error())
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required templates: %s", strings.Join(missing, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"crypto/tls"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/api"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/quota"
+)
+
+// Reload applies the reloadable subset of next (see
+// config.Config.WithReloadable) and re-reads the TLS certificate.
+//
+// next must be valid, as config.Load guarantees. Changes to anything
+// else are logged and wait for a restart. Everything is prepared and
+// validated before anything is applied: if the certificate cannot be
+// re-read or any setting is rejected, the running configuration stays
+// in effect as a whole.
+func (s *Server) Reload(next *config.Config) error {
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	running := s.cfg
+
+	//-----------------------------------------
+	// Prepare (may fail)
+	//-----------------------------------------
+
+	level, err := zapcore.ParseLevel(next.Log.Level)
+	if err != nil {
+		return err
+	}
+
+	var cert *tls.Certificate
+	if s.certificate != nil {
+		if cert, err = s.certificate.load(); err != nil {
+			return err
+		}
+	}
+
+	if err := catalog.ValidatePins(next.Templates.Pinned); err != nil {
+		return err
+	}
+
+	authzCfg := authzConfig(running.WithReloadable(next))
+	if err := authzCfg.Validate(); err != nil {
+		return err
+	}
+
+	//-----------------------------------------
+	// Apply (validated above, cannot fail)
+	//-----------------------------------------
+
+	if cert != nil {
+		s.certificate.current.Store(cert)
+	}
+
+	_ = s.templates.Pin(next.Templates.Pinned)
+	_ = s.authorizer.Update(authzCfg)
+
+	s.policy.Set(newPolicy(next))
+	s.blueprints.Set(newBlueprints(next), next.Environments.DefaultBlueprint)
+
+	// Only a changed level is applied, so that a level set at runtime
+	// through the admin API survives unrelated reloads.
+	if next.Log.Level != running.Log.Level {
+		s.logLevel.SetLevel(level)
+	}
+
+	s.cfg = running.WithReloadable(next)
+
+	if keys := config.RestartRequired(running, next); len(keys) > 0 {
+		s.logger.Warn("configuration changes require a restart",
+			zap.Strings("keys", keys),
+		)
+	}
+
+	s.logger.Info("configuration reloaded", zap.Any("config", s.cfg.Redacted()))
+
+	return nil
+}
+
+//
+// ---- Helpers ----
+//
+
+func authzConfig(cfg *config.Config) authz.Config {
+	return authz.Config{
+		AdminUsers:     cfg.Auth.AdminUsers,
+		AdminGroups:    cfg.Auth.AdminGroups,
+		Read:           authz.ReadPolicy(cfg.Auth.ReadPolicy),
+		AllowAnonymous: cfg.Auth.Disabled,
+	}
+}
+
+func newPolicy(cfg *config.Config) api.Policy {
+
+	q := cfg.Quotas.Defaults
+
+	return api.Policy{
+		DefaultTTL: cfg.Environments.DefaultTTL.Duration,
+		MaxTTL:     cfg.Environments.MaxTTL.Duration,
+		DefaultQuotas: quota.Limits{
+			MaxEnvironments:             q.MaxEnvironments,
+			MaxTTL:                      q.MaxTTL.Duration,
+			MaxConcurrentRuns:           q.MaxConcurrentRuns,
+			MaxEnvironmentHoursPerMonth: q.MaxEnvironmentHoursPerMonth,
+		},
+		ReservedNamespaces: reservedNamespaces(cfg),
+	}
+}
+
+// systemNamespaces exist in every cluster and are never environments.
+var systemNamespaces = []string{"default", "kube-system", "kube-public", "kube-node-lease"}
+
+// reservedNamespaces are the system namespaces and every namespace the
+// control plane submits workflows or reads templates in.
+func reservedNamespaces(cfg *config.Config) map[string]bool {
+
+	reserved := make(map[string]bool)
+	for _, ns := range systemNamespaces {
+		reserved[ns] = true
+	}
+
+	reserved[cfg.Executor.Namespace] = true
+	if cfg.Templates.Namespace != "" {
+		reserved[cfg.Templates.Namespace] = true
+	}
+	for _, ns := range cfg.Executor.ServiceNamespaces {
+		reserved[ns] = true
+	}
+	for _, ns := range cfg.Executor.TeamNamespaces {
+		reserved[ns] = true
+	}
+
+	return reserved
+}
+
+// defaultStatePrefix is where Terraform-backed environments keep their
+// state unless the blueprint says otherwise.
+const defaultStatePrefix = "environments"
+
+func newBlueprints(cfg *config.Config) []blueprint.Blueprint {
+
+	out := make([]blueprint.Blueprint, 0, len(cfg.Blueprints))
+
+	for name, b := range cfg.Blueprints {
+		bp := blueprint.Blueprint{
+			Name:            name,
+			Description:     b.Description,
+			CreateTemplate:  b.CreateTemplate,
+			DestroyTemplate: b.DestroyTemplate,
+			TTLTemplate:     b.TTLTemplate,
+			DefaultTTL:      b.DefaultTTL.Duration,
+			Parameters:      b.Parameters,
+		}
+
+		if tf := b.Terraform; tf != nil {
+			bp.Terraform = &blueprint.Terraform{
+				Module:      tf.Module,
+				Version:     tf.Version,
+				StatePrefix: tf.StatePrefix,
+			}
+			if bp.Terraform.StatePrefix == "" {
+				bp.Terraform.StatePrefix = defaultStatePrefix
+			}
+		}
+
+		for _, o := range b.Outputs {
+			bp.Outputs = append(bp.Outputs, blueprint.Output{
+				Name:        o.Name,
+				Type:        blueprint.OutputType(o.Type),
+				Description: o.Description,
+			})
+		}
+
+		out = append(out, bp)
+	}
+
+	return out
+}
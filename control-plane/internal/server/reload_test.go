@@ -0,0 +1,84 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/api"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+)
+
+func TestReload(t *testing.T) {
+
+	s := newReloadServer(t)
+	alice := &auth.Principal{Subject: "alice", Method: auth.MethodOIDC}
+
+	next := config.Default()
+	next.Auth.Disabled = true
+	next.Log.Level = "debug"
+	next.Auth.AdminUsers = []string{"alice"}
+	next.Environments.MaxTTL = config.Duration{Duration: 24 * time.Hour}
+	next.HTTP.Address = ":9090"
+
+	if err := s.Reload(next); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.logLevel.Level() != zapcore.DebugLevel {
+		t.Fatalf("log level = %s", s.logLevel.Level())
+	}
+	if !s.authorizer.IsAdmin(alice) {
+		t.Fatal("admin users not reloaded")
+	}
+	if got := s.policy.Get().MaxTTL; got != 24*time.Hour {
+		t.Fatalf("policy max ttl = %s", got)
+	}
+	if s.cfg.HTTP.Address != ":8080" {
+		t.Fatalf("http.address = %q, want it to wait for a restart", s.cfg.HTTP.Address)
+	}
+
+	// A rejected reload keeps the running configuration as a whole.
+	bad := config.Default()
+	bad.Auth.Disabled = true
+	bad.Log.Level = "info"
+	bad.Templates.Pinned = map[string]string{"build": "x"}
+
+	if err := s.Reload(bad); err == nil {
+		t.Fatal("Reload() accepted an unknown pin purpose")
+	}
+	if s.logLevel.Level() != zapcore.DebugLevel || !s.authorizer.IsAdmin(alice) || s.cfg.Log.Level != "debug" {
+		t.Fatal("a rejected reload changed the running configuration")
+	}
+}
+
+// ---- Helpers ----
+
+func newReloadServer(t *testing.T) *Server {
+
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Auth.Disabled = true
+
+	authorizer, err := authz.New(authzConfig(cfg), zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Server{
+		cfg:        cfg,
+		logger:     zap.NewNop(),
+		logLevel:   zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		authorizer: authorizer,
+		templates:  catalog.New(nil, cfg.Executor.Namespace),
+		blueprints: blueprint.NewRegistry(newBlueprints(cfg), cfg.Environments.DefaultBlueprint),
+		policy:     api.NewPolicySource(newPolicy(cfg)),
+	}
+}
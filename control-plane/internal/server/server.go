@@ -2,38 +2,192 @@ package server
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/api"
-	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/audit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/auth"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/authz"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/blueprint"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/catalog"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/contract"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/logging"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/manifest"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/metrics"
 	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/providers/github"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/ratelimit"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/tracing"
 	"go.uber.org/zap"
 )
 
 type Server struct {
-	httpServer *http.Server
+	httpServer      *http.Server
+	auditLog        audit.Store
+	shutdownTracing func(context.Context) error
+
+	// Reloadable state; see Reload.
+	reloadMu    sync.Mutex
+	cfg         *config.Config
+	logger      *zap.Logger
+	logLevel    zap.AtomicLevel
+	authorizer  *authz.Authorizer
+	templates   *catalog.Catalog
+	blueprints  *blueprint.Registry
+	policy      *api.PolicySource
+	certificate *certificate
+
+	// stopBackground cancels catalog refresh and other
+	// background loops owned by the server.
+	stopBackground context.CancelFunc
 }
 
+// environmentObserveInterval controls how fresh the environment
+// phase metrics are.
+const environmentObserveInterval = 30 * time.Second
+
+// New wires the control plane. logger and logLevel come from main, so
+// LOG_LEVEL applies everywhere and the level can be changed at runtime.
 func New(
-	address string,
-	readTimeout time.Duration,
-	writeTimeout time.Duration,
+	cfg *config.Config,
+	logger *zap.Logger,
+	logLevel zap.AtomicLevel,
 ) (*Server, error) {
 
+	//-----------------------------------------
+	// Authentication
+	//-----------------------------------------
+
+	authenticators, err := newAuthenticators(context.Background(), cfg.Auth, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizer, err := authz.New(authzConfig(cfg), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	//-----------------------------------------
+	// TLS
+	//-----------------------------------------
+
+	var cert *certificate
+	if cfg.HTTP.TLS.Enabled() {
+		if cert, err = newCertificate(cfg.HTTP.TLS); err != nil {
+			return nil, err
+		}
+	}
+
+	//-----------------------------------------
+	// Tracing
+	//-----------------------------------------
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		File:        cfg.Tracing.File,
+		SampleRatio: cfg.Tracing.SampleRatio,
+		ServiceName: cfg.ServiceName,
+		Environment: cfg.Environment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	//-----------------------------------------
+	// Audit log
+	//-----------------------------------------
+
+	auditLog, err := newAuditStore(cfg.Audit, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	//-----------------------------------------
 	// Executor (Execution Plane Bridge)
 	//-----------------------------------------
 
-	clients, err := executor.NewClients()
+	backend, err := newExecutionBackend(cfg.Executor, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	argoExecutor := executor.NewArgoSDKExecutor(
-		clients,
-		"argo", // move to config soon
+	// Every submission is measured and audited, whichever
+	// orchestrator makes it. Template submissions are checked against
+	// the template's declared parameters first, so rejections are
+	// measured and audited too.
+	submitter := audit.NewExecutor(
+		tracing.NewExecutor(metrics.NewExecutor(
+			contract.NewExecutor(backend.exec, backend.templates, logger),
+		)),
+		auditLog,
+		logger,
+	)
+
+	// Registered services and teams. Team namespaces registered through
+	// the API take precedence over configured team routes.
+	store := api.NewServiceStore()
+
+	router := orchestrator.NewNamespaceRouter(
+		cfg.Executor.Namespace,
+		cfg.Executor.ServiceNamespaces,
+		cfg.Executor.TeamNamespaces,
+	).WithTeams(store)
+
+	placementRules := make([]orchestrator.PlacementRule, 0, len(cfg.Executor.Placement))
+	for _, p := range cfg.Executor.Placement {
+		placementRules = append(placementRules, orchestrator.PlacementRule{
+			Service: p.Service,
+			Team:    p.Team,
+			Cluster: p.Cluster,
+		})
+	}
+
+	placer, err := orchestrator.NewClusterPlacer(
+		backend.defaultCluster,
+		backend.clusters,
+		placementRules,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	//-----------------------------------------
+	// Template catalog (discovered, not hardcoded)
+	//-----------------------------------------
+
+	templateNamespace := cfg.Templates.Namespace
+	if templateNamespace == "" {
+		templateNamespace = cfg.Executor.Namespace
+	}
+
+	templates := catalog.New(backend.templates, templateNamespace)
+
+	if err := templates.Pin(cfg.Templates.Pinned); err != nil {
+		return nil, err
+	}
+
+	// A failed first refresh is not fatal: templates may be applied
+	// after the control plane starts, and the refresh loop picks them up.
+	if err := templates.Refresh(context.Background()); err != nil {
+		logger.Warn("template catalog refresh failed", zap.Error(err))
+	}
+
+	blueprints := blueprint.NewRegistry(newBlueprints(cfg), cfg.Environments.DefaultBlueprint)
+
+	if err := preflightTemplates(cfg, backend, templates, blueprints, logger); err != nil {
+		return nil, err
+	}
+
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+
+	go templates.Run(bgCtx, cfg.Templates.RefreshInterval.Duration, func(err error) {
+		logger.Warn("template catalog refresh failed", zap.Error(err))
+	})
 
 	//-----------------------------------------
 	// Orchestrator (Intent Layer)
@@ -42,50 +196,175 @@ func New(
 	// IMPORTANT:
 	// Do NOT declare pointers without constructing them.
 	// No `var envOrchestrator *...`
-	envOrchestrator := orchestrator.NewArgoEnvironmentOrchestrator(
-		argoExecutor,
+	argoEnvironments := orchestrator.NewArgoEnvironmentOrchestrator(
+		submitter,
+		templates,
+		blueprints,
+		router,
+		placer,
+	)
+
+	envOrchestrator := tracing.NewEnvironmentOrchestrator(argoEnvironments)
+
+	ciOrchestrator := tracing.NewCIOrchestrator(
+		orchestrator.NewArgoCIOrchestrator(
+			submitter,
+			templates,
+			router,
+		),
+	)
+
+	deployOrchestrator := tracing.NewDeploymentOrchestrator(
+		orchestrator.NewArgoDeploymentOrchestrator(
+			submitter,
+			templates,
+		),
 	)
 
+	// Polling is not traced: it would start a root trace per
+	// environment every interval.
+	observer := metrics.NewEnvironmentObserver(store, argoEnvironments, logger)
+	go observer.Run(bgCtx, environmentObserveInterval)
+
 	//-----------------------------------------
-	// Logger (temporary bootstrap logger)
+	// Repository provider + pipeline manifests
 	//-----------------------------------------
 
-	// Senior recommendation:
-	// Inject this from main soon instead of constructing here.
-	logger, err := zap.NewProduction()
-	if err != nil {
-		return nil, err
-	}
+	repositories := github.New(cfg.Providers.GitHubToken)
+
+	manifests := manifest.NewLoader(repositories)
+
+	//-----------------------------------------
+	// Readiness (dependency checks)
+	//-----------------------------------------
+
+	readiness := newReadiness(
+		backend,
+		templates,
+		templateNamespace,
+		auditLog,
+		repositories,
+		logger,
+	)
 
 	//-----------------------------------------
 	// Router
 	//-----------------------------------------
 
-	handler := api.NewRouter(
+	policy := api.NewPolicySource(newPolicy(cfg))
+
+	search := orchestrator.NewArgoWorkflowSearch(
+		metrics.NewWorkflowLister(backend.workflows),
+		router,
+		placer,
+	)
+
+	mux := api.NewRouter(
+		store,
 		envOrchestrator, // interface satisfied
+		ciOrchestrator,
+		deployOrchestrator,
+		search,
+		templates,
+		blueprints,
+		manifests,
+		authorizer,
+		ratelimit.NewConcurrency(cfg.RateLimit.MaxInFlightPerService),
+		policy,
+		auditLog,
+		readiness,
+		logLevel,
 		logger,
 	)
 
+	var handler http.Handler = mux
+
+	// Rate limits key on the principal, so they run inside authentication.
+	handler = ratelimit.Middleware(
+		newRateLimiters(cfg.RateLimit),
+		classifyRequest(),
+		publicPaths,
+		logger,
+	)(handler)
+
+	handler = audit.Identify(handler)
+
+	handler = auth.Middleware(authenticators, publicPaths, logger)(handler)
+
+	// Audit outside authentication and rate limits so that rejections,
+	// 401s included, are recorded too.
+	handler = audit.Middleware(auditLog, logger)(handler)
+
+	// Per-IP budget before authentication, and before audit so that a
+	// flood cannot fill the audit log.
+	handler = ratelimit.ClientMiddleware(
+		newClientLimiter(cfg.RateLimit),
+		publicPaths,
+		logger,
+	)(handler)
+
+	// Outermost, so that rejected requests are logged, traced and
+	// measured too.
+	handler = logging.Middleware(logger, mux.Handler)(handler)
+	handler = tracing.Middleware(mux.Handler)(handler)
+	handler = metrics.Middleware(mux.Handler)(handler)
+
+	// Scrapes bypass the API middleware chain entirely.
+	root := http.NewServeMux()
+	root.Handle("/metrics", metrics.Handler())
+	root.Handle("/", handler)
+
 	//-----------------------------------------
 	// HTTP Server
 	//-----------------------------------------
 
 	httpSrv := &http.Server{
-		Addr:         address,
-		Handler:      handler,
-		ReadTimeout:  readTimeout,
-		WriteTimeout: writeTimeout,
+		Addr:         cfg.HTTP.Address,
+		Handler:      root,
+		ReadTimeout:  cfg.HTTP.ReadTimeout.Duration,
+		WriteTimeout: cfg.HTTP.WriteTimeout.Duration,
+		IdleTimeout:  cfg.HTTP.IdleTimeout.Duration,
+	}
+
+	if cert != nil {
+		httpSrv.TLSConfig = cert.tlsConfig()
 	}
 
 	return &Server{
-		httpServer: httpSrv,
+		httpServer:      httpSrv,
+		auditLog:        auditLog,
+		shutdownTracing: shutdownTracing,
+		cfg:             cfg,
+		logger:          logger,
+		logLevel:        logLevel,
+		authorizer:      authorizer,
+		templates:       templates,
+		blueprints:      blueprints,
+		policy:          policy,
+		certificate:     cert,
+		stopBackground:  stopBackground,
 	}, nil
 }
 
+// Start serves HTTPS when a certificate is configured, HTTP otherwise.
 func (s *Server) Start() error {
+	if s.certificate != nil {
+		return s.httpServer.ListenAndServeTLS("", "")
+	}
 	return s.httpServer.ListenAndServe()
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	s.stopBackground()
+
+	err := s.httpServer.Shutdown(ctx)
+
+	if closer, ok := s.auditLog.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
+	// Flush spans once in-flight requests have drained.
+	_ = s.shutdownTracing(ctx)
+
+	return err
 }
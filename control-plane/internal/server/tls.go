@@ -0,0 +1,61 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/config"
+)
+
+// certificate serves the configured TLS certificate and re-reads it
+// on reload, so rotated Secrets are picked up by new connections
+// without a restart.
+type certificate struct {
+	cfg     config.TLSConfig
+	current atomic.Pointer[tls.Certificate]
+}
+
+func newCertificate(cfg config.TLSConfig) (*certificate, error) {
+
+	c := &certificate{cfg: cfg}
+
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// reload keeps the previous certificate if the files cannot be read,
+// e.g. halfway through a Secret update.
+func (c *certificate) reload() error {
+
+	cert, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	c.current.Store(cert)
+	return nil
+}
+
+// load reads the certificate files without serving them.
+func (c *certificate) load() (*tls.Certificate, error) {
+
+	cert, err := tls.LoadX509KeyPair(c.cfg.CertFile, c.cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls certificate: %w", err)
+	}
+
+	return &cert, nil
+}
+
+func (c *certificate) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return c.current.Load(), nil
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/executor"
+)
+
+// Executor spans every call to the execution plane made within a trace.
+//
+// The executors themselves write the trace into submitted workflows
+// (see executor.TraceAnnotations), so submissions are traceable
+// whether or not this decorator is installed.
+type Executor struct {
+	inner executor.WorkflowExecutor
+}
+
+func NewExecutor(inner executor.WorkflowExecutor) *Executor {
+	return &Executor{inner: inner}
+}
+
+func (e *Executor) SubmitFromTemplate(
+	ctx context.Context,
+	target executor.Target,
+	templateName string,
+	generateName string,
+	parameters map[string]string,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	ctx, span := e.start(ctx, "executor.submit_from_template", target)
+	span.SetAttributes(attribute.String("platform.workflow.template", templateName))
+
+	created, err := e.inner.SubmitFromTemplate(
+		ctx,
+		target,
+		templateName,
+		generateName,
+		parameters,
+		labels,
+	)
+
+	setWorkflow(span, created)
+	end(span, err)
+
+	return created, err
+}
+
+func (e *Executor) SubmitWorkflow(
+	ctx context.Context,
+	target executor.Target,
+	workflow *wf.Workflow,
+	labels map[string]string,
+) (*wf.Workflow, error) {
+
+	ctx, span := e.start(ctx, "executor.submit_workflow", target)
+
+	created, err := e.inner.SubmitWorkflow(ctx, target, workflow, labels)
+
+	setWorkflow(span, created)
+	end(span, err)
+
+	return created, err
+}
+
+func (e *Executor) GetWorkflow(
+	ctx context.Context,
+	target executor.Target,
+	name string,
+) (*wf.Workflow, error) {
+
+	ctx, span := e.start(ctx, "executor.get_workflow", target)
+	span.SetAttributes(attribute.String("platform.workflow.name", name))
+
+	workflow, err := e.inner.GetWorkflow(ctx, target, name)
+	end(span, err)
+
+	return workflow, err
+}
+
+func (e *Executor) Cancel(
+	ctx context.Context,
+	target executor.Target,
+	name string,
+) error {
+
+	ctx, span := e.start(ctx, "executor.cancel", target)
+	span.SetAttributes(attribute.String("platform.workflow.name", name))
+
+	err := e.inner.Cancel(ctx, target, name)
+	end(span, err)
+
+	return err
+}
+
+// start opens a child span. Calls outside a trace (background polling)
+// get a no-op span rather than a root trace of their own.
+func (e *Executor) start(
+	ctx context.Context,
+	name string,
+	target executor.Target,
+) (context.Context, trace.Span) {
+
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	return tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("platform.cluster", target.Cluster),
+			attribute.String("platform.namespace", target.Namespace),
+		),
+	)
+}
+
+func setWorkflow(span trace.Span, workflow *wf.Workflow) {
+	if workflow == nil {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("platform.workflow.name", workflow.Name),
+		attribute.String("platform.workflow.uid", string(workflow.UID)),
+	)
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RouteFunc returns the route pattern serving a request, or "" when
+// none matches. *http.ServeMux's Handler method has this shape.
+type RouteFunc func(r *http.Request) (http.Handler, string)
+
+// Middleware starts a server span per request, continuing the trace of
+// an incoming traceparent header (e.g. from a CI system), and returns
+// the trace ID to the client in the traceparent response header.
+func Middleware(route RouteFunc) func(http.Handler) http.Handler {
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			name := r.Method
+			if _, pattern := route(r); pattern != "" {
+				name += " " + pattern
+			}
+
+			ctx, span := tracer().Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("url.path", r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(w.Header()))
+
+			rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			span.SetAttributes(attribute.Int("http.response.status_code", rw.status))
+			if rw.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rw.status))
+			}
+		})
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
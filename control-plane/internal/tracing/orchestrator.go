@@ -0,0 +1,191 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	wf "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/orchestrator"
+	"github.com/marco13-moo/self-service-cicd-platform/control-plane/internal/pipeline"
+)
+
+// EnvironmentOrchestrator spans every environment intent.
+type EnvironmentOrchestrator struct {
+	inner orchestrator.EnvironmentOrchestrator
+}
+
+func NewEnvironmentOrchestrator(inner orchestrator.EnvironmentOrchestrator) *EnvironmentOrchestrator {
+	return &EnvironmentOrchestrator{inner: inner}
+}
+
+func (o *EnvironmentOrchestrator) Create(
+	ctx context.Context,
+	spec orchestrator.EnvironmentSpec,
+) (*orchestrator.Environment, error) {
+
+	ctx, span := tracer().Start(ctx, "environment.create")
+	span.SetAttributes(
+		attribute.String("platform.environment", spec.Name),
+		attribute.String("platform.service", spec.Service),
+		attribute.String("platform.team", spec.Team),
+		attribute.String("platform.cluster", spec.Cluster),
+		attribute.String("platform.ttl", spec.TTL.String()),
+	)
+
+	env, err := o.inner.Create(ctx, spec)
+	end(span, err)
+
+	return env, err
+}
+
+func (o *EnvironmentOrchestrator) Destroy(
+	ctx context.Context,
+	env *orchestrator.Environment,
+) (*orchestrator.WorkflowReference, error) {
+
+	ctx, span := tracer().Start(ctx, "environment.destroy")
+	span.SetAttributes(
+		attribute.String("platform.environment", env.Spec.Name),
+		attribute.String("platform.service", env.Spec.Service),
+	)
+
+	ref, err := o.inner.Destroy(ctx, env)
+	end(span, err)
+
+	return ref, err
+}
+
+func (o *EnvironmentOrchestrator) Extend(
+	ctx context.Context,
+	env *orchestrator.Environment,
+	ttl time.Duration,
+) (*orchestrator.WorkflowReference, error) {
+
+	ctx, span := tracer().Start(ctx, "environment.extend")
+	span.SetAttributes(
+		attribute.String("platform.environment", env.Spec.Name),
+		attribute.String("platform.service", env.Spec.Service),
+		attribute.String("platform.ttl", ttl.String()),
+	)
+
+	ref, err := o.inner.Extend(ctx, env, ttl)
+	end(span, err)
+
+	return ref, err
+}
+
+func (o *EnvironmentOrchestrator) GetCreateStatus(
+	ctx context.Context,
+	env *orchestrator.Environment,
+) (*wf.WorkflowStatus, error) {
+
+	ctx, span := tracer().Start(ctx, "environment.create_status")
+	span.SetAttributes(attribute.String("platform.environment", env.Spec.Name))
+
+	status, err := o.inner.GetCreateStatus(ctx, env)
+	end(span, err)
+
+	return status, err
+}
+
+func (o *EnvironmentOrchestrator) GetTTLStatus(
+	ctx context.Context,
+	env *orchestrator.Environment,
+) (*wf.WorkflowStatus, error) {
+
+	ctx, span := tracer().Start(ctx, "environment.ttl_status")
+	span.SetAttributes(attribute.String("platform.environment", env.Spec.Name))
+
+	status, err := o.inner.GetTTLStatus(ctx, env)
+	end(span, err)
+
+	return status, err
+}
+
+// CIOrchestrator spans every CI run intent.
+type CIOrchestrator struct {
+	inner orchestrator.CIOrchestrator
+}
+
+func NewCIOrchestrator(inner orchestrator.CIOrchestrator) *CIOrchestrator {
+	return &CIOrchestrator{inner: inner}
+}
+
+func (o *CIOrchestrator) Run(
+	ctx context.Context,
+	spec orchestrator.RunSpec,
+) (*orchestrator.Run, error) {
+
+	ctx, span := tracer().Start(ctx, "ci.run")
+	span.SetAttributes(
+		attribute.String("platform.service", spec.Service),
+		attribute.String("platform.team", spec.Team),
+	)
+
+	run, err := o.inner.Run(ctx, spec)
+	end(span, err)
+
+	return run, err
+}
+
+func (o *CIOrchestrator) RunPipeline(
+	ctx context.Context,
+	run orchestrator.RunSpec,
+	spec *pipeline.Spec,
+) (*orchestrator.Run, error) {
+
+	ctx, span := tracer().Start(ctx, "ci.run_pipeline")
+	span.SetAttributes(
+		attribute.String("platform.service", run.Service),
+		attribute.String("platform.team", run.Team),
+	)
+
+	out, err := o.inner.RunPipeline(ctx, run, spec)
+	end(span, err)
+
+	return out, err
+}
+
+// DeploymentOrchestrator spans every deployment intent.
+type DeploymentOrchestrator struct {
+	inner orchestrator.DeploymentOrchestrator
+}
+
+func NewDeploymentOrchestrator(inner orchestrator.DeploymentOrchestrator) *DeploymentOrchestrator {
+	return &DeploymentOrchestrator{inner: inner}
+}
+
+func (o *DeploymentOrchestrator) Deploy(
+	ctx context.Context,
+	env *orchestrator.Environment,
+	spec orchestrator.DeploymentSpec,
+) (*orchestrator.Deployment, error) {
+
+	ctx, span := tracer().Start(ctx, "deployment.deploy")
+	span.SetAttributes(
+		attribute.String("platform.environment", env.Spec.Name),
+		attribute.String("platform.service", spec.Service),
+		attribute.String("platform.image", spec.Image),
+	)
+
+	d, err := o.inner.Deploy(ctx, env, spec)
+	end(span, err)
+
+	return d, err
+}
+
+func (o *DeploymentOrchestrator) GetDeploymentStatus(
+	ctx context.Context,
+	d *orchestrator.Deployment,
+) (*wf.WorkflowStatus, error) {
+
+	ctx, span := tracer().Start(ctx, "deployment.status")
+	span.SetAttributes(attribute.String("platform.workflow", d.Workflow.Name))
+
+	status, err := o.inner.GetDeploymentStatus(ctx, d)
+	end(span, err)
+
+	return status, err
+}
@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// instrumentation names the tracer of every span the control plane creates.
+const instrumentation = "github.com/marco13-moo/self-service-cicd-platform/control-plane"
+
+// Config selects where spans go.
+//
+// The OTLP exporter is configured through the standard OTEL_EXPORTER_OTLP_*
+// environment variables (endpoint, headers, TLS). The file exporter
+// writes one JSON span per line, for clusters without a collector.
+type Config struct {
+	Exporter    string
+	File        string
+	SampleRatio float64
+	ServiceName string
+	Environment string
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator, and returns a func flushing and stopping the exporter.
+//
+// The propagator is installed even without an exporter, so incoming
+// traceparent headers still reach submitted workflows.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   io.Closer
+		err      error
+	)
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+
+	case ExporterFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("tracing: file exporter requires a file")
+		}
+		var f *os.File
+		f, err = os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err == nil {
+			closer = f
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+		}
+
+	default:
+		return nil, fmt.Errorf(
+			"tracing: unknown exporter %q (want %s, %s, %s or %s)",
+			cfg.Exporter, ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing: create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("deployment.environment", cfg.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: build resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return err
+	}, nil
+}
+
+// tracer is resolved on every use so that it follows the provider
+// installed by Setup.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// end records err on span, if any, and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}